@@ -1,17 +1,32 @@
 package serializer
 
 import (
+	"bytes"
+	"fmt"
 	"math"
 	"reflect"
+	"strconv"
+	"sync"
 	"unsafe"
 
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/binaryx"
 	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/bytesx"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
 )
 
-type RawBinarySerializer struct{}
+type RawBinarySerializer struct {
+	opts binaryx.Options
+	pool *bytesx.Pool
+	// typed holds the codecs of Encode, Decode and NewCodec, per type
+	typed *sync.Map // map[reflect.Type]*binaryx.TypedCodec, nil when typ has none
+}
 
-func NewRawBinarySerializer() *RawBinarySerializer {
-	return &RawBinarySerializer{}
+func NewRawBinarySerializer(opts ...BinaryOption) *RawBinarySerializer {
+	return &RawBinarySerializer{
+		opts:  binaryx.NewOptions(append([]BinaryOption{WithZeroCopy(true)}, opts...)...),
+		pool:  bytesx.NewPool(),
+		typed: &sync.Map{},
+	}
 }
 
 // ################################################################################################################## \\
@@ -19,16 +34,35 @@ func NewRawBinarySerializer() *RawBinarySerializer {
 // ################################################################################################################## \\
 
 func (s *RawBinarySerializer) Serialize(data interface{}) ([]byte, error) {
-	return s.encode(data), nil
+	bbw := s.pool.Get()
+	defer s.pool.Put(bbw)
+
+	bs, err := s.serializeTo(bbw, data)
+	if err != nil {
+		return nil, fmt.Errorf(models.EncodeErrMsg, err)
+	}
+
+	return bytes.Clone(bs), nil
 }
 
 func (s *RawBinarySerializer) Deserialize(data []byte, target interface{}) error {
-	s.decode(data, target)
+	if err := s.deserialize(data, target); err != nil {
+		return fmt.Errorf(models.DecodeErrMsg, err)
+	}
+
 	return nil
 }
 
 func (s *RawBinarySerializer) DataRebind(payload interface{}, target interface{}) error {
-	s.decode(s.encode(payload), target)
+	bs, err := s.encode(payload)
+	if err != nil {
+		return fmt.Errorf(models.RebinderErrMsg, err)
+	}
+
+	if err = s.decode(bs, target); err != nil {
+		return fmt.Errorf(models.RebinderErrMsg, err)
+	}
+
 	return nil
 }
 
@@ -44,15 +78,82 @@ func (s *RawBinarySerializer) Unmarshal(data []byte, target interface{}) error {
 	return s.Deserialize(data, target)
 }
 
+// Append appends the payload of data to dst and returns the extended buffer, in the style of strconv.AppendInt.
+// The payload is written straight into the spare capacity of dst, which is only reallocated when it runs out.
+// dst is returned unchanged on errors.
+func (s *RawBinarySerializer) Append(dst []byte, data interface{}) ([]byte, error) {
+	bbw := s.pool.GetAppender(dst)
+	defer s.pool.PutAppender(bbw)
+
+	bs, err := s.serializeTo(bbw, data)
+	if err != nil {
+		return dst, fmt.Errorf(models.EncodeErrMsg, err)
+	}
+
+	return bs, nil
+}
+
+// SerializeTo hands the payload of data to fn, sparing the copy made by Serialize to return a buffer the caller owns.
+// The payload is written into a pooled buffer that is only valid until fn returns. The error of fn is returned as is.
+func (s *RawBinarySerializer) SerializeTo(data interface{}, fn func(bs []byte) error) error {
+	bbw := s.pool.Get()
+	defer s.pool.Put(bbw)
+
+	bs, err := s.serializeTo(bbw, data)
+	if err != nil {
+		return fmt.Errorf(models.EncodeErrMsg, err)
+	}
+
+	return fn(bs)
+}
+
 // ################################################################################################################## \\
 // private encoder implementation
 // ################################################################################################################## \\
 
-func (s *RawBinarySerializer) encode(data interface{}) []byte {
-	bbw := bytesx.NewWriter(make([]byte, 1<<6))
+// serializeTo appends the payload of data to bbw, along with the envelope, fingerprint and checksum enabled on s.
+func (s *RawBinarySerializer) serializeTo(bbw *bytesx.Writer, data interface{}) ([]byte, error) {
+	start := s.putHeader(bbw, reflect.TypeOf(data))
+	if _, err := s.encodeTo(bbw, data); err != nil {
+		return nil, err
+	}
+
+	return s.putTrailer(bbw, start), nil
+}
+
+// putHeader appends the envelope and fingerprint enabled on s for a payload of type typ to bbw, returning where they
+// start.
+func (s *RawBinarySerializer) putHeader(bbw *bytesx.Writer, typ reflect.Type) int {
+	start := bbw.Len()
+	if s.opts.Envelope {
+		binaryx.PutEnvelope(bbw, binaryx.FormatRawBinary, s.opts.EnvelopeFlags())
+	}
+
+	if s.opts.SchemaFingerprint {
+		binaryx.PutFingerprint(bbw, typ, s.opts)
+	}
+
+	return start
+}
+
+// putTrailer appends the checksum enabled on s, covering bbw from start onwards, returning everything bbw holds.
+func (s *RawBinarySerializer) putTrailer(bbw *bytesx.Writer, start int) []byte {
+	if s.opts.Checksum {
+		binaryx.PutChecksum(bbw, start)
+	}
+
+	return bbw.Bytes()
+}
+
+// encode allocates a buffer of its own rather than a pooled one, as the values DataRebind decodes from it may alias it.
+func (s *RawBinarySerializer) encode(data interface{}) ([]byte, error) {
+	return s.encodeTo(bytesx.NewWriter(make([]byte, 1<<6)), data)
+}
 
-	if s.serializePrimitive(bbw, data) {
-		return bbw.Bytes()
+// encodeTo appends data to bbw, returning everything bbw holds.
+func (s *RawBinarySerializer) encodeTo(bbw *bytesx.Writer, data interface{}) ([]byte, error) {
+	if !s.opts.Varint && s.serializePrimitive(bbw, data) {
+		return bbw.Bytes(), nil
 	}
 
 	value := reflect.ValueOf(data)
@@ -60,130 +161,292 @@ func (s *RawBinarySerializer) encode(data interface{}) []byte {
 		value = value.Elem()
 	}
 
-	if value.Kind() == reflect.Struct {
-		s.structEncode(bbw, &value)
-		return bbw.Bytes()
+	if !value.IsValid() {
+		return bbw.Bytes(), nil
 	}
 
-	if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
-		s.sliceArrayEncode(bbw, &value)
-		return bbw.Bytes()
+	if kind := binaryx.UnsupportedKind(value.Type()); kind != reflect.Invalid {
+		return nil, &models.UnsupportedTypeError{Type: value.Type(), Kind: kind}
 	}
 
-	if value.Kind() == reflect.Map {
-		s.mapEncode(bbw, &value)
-		return bbw.Bytes()
+	if s.codecEncode(bbw, value) || s.marshalerEncode(bbw, value) {
+		return bbw.Bytes(), bbw.Err()
 	}
 
-	if value.Kind() == reflect.Chan {
-		return nil
+	if s.serializeReflectPrimitive(bbw, &value) {
+		return bbw.Bytes(), bbw.Err()
 	}
 
-	return bbw.Bytes()
+	if value.Kind() == reflect.Interface {
+		s.interfaceEncode(bbw, value.Interface())
+		return bbw.Bytes(), bbw.Err()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+		s.planOf(value.Type()).Encode(bbw, value)
+	}
+
+	return bbw.Bytes(), bbw.Err()
 }
 
-func (s *RawBinarySerializer) reflectEncode(value reflect.Value) []byte {
-	bbw := bytesx.NewWriter(make([]byte, 1<<6))
+func (s *RawBinarySerializer) reflectEncode(bbw *bytesx.Writer, value reflect.Value) {
+	s.planOf(value.Type()).Encode(bbw, value)
+}
 
-	if s.serializeReflectPrimitive(bbw, &value) {
-		return bbw.Bytes()
+// deserialize reads a payload written by serializeTo into target.
+func (s *RawBinarySerializer) deserialize(data []byte, target interface{}) error {
+	value, err := binaryx.DecodeTarget(target)
+	if err != nil {
+		return err
 	}
 
-	if value.Kind() == reflect.Ptr {
-		if value.IsNil() {
-			bbw.Put(1)
+	bbr := bytesx.NewReader(data)
+	d, err := s.open(bbr, reflect.TypeOf(target))
+	if err != nil {
+		return err
+	}
 
-			return bbw.Bytes()
-		}
+	return d.decodeValue(bbr, value)
+}
 
-		bbw.Put(0)
-		value = value.Elem()
+func (s *RawBinarySerializer) decode(data []byte, target interface{}) error {
+	return s.decodeFrom(bytesx.NewReader(data), target)
+}
+
+// decodeFrom reads target from the cursor of bbr onwards.
+func (s *RawBinarySerializer) decodeFrom(bbr *bytesx.Reader, target interface{}) error {
+	value, err := binaryx.DecodeTarget(target)
+	if err != nil {
+		return err
 	}
 
-	if value.Kind() == reflect.Struct {
-		s.structEncode(bbw, &value)
-		return bbw.Bytes()
+	return s.decodeValue(bbr, value)
+}
+
+// decodeValue reads the value a target returned by binaryx.DecodeTarget points to.
+func (s *RawBinarySerializer) decodeValue(bbr *bytesx.Reader, value reflect.Value) error {
+	if s.codecDecode(bbr, value) || s.marshalerDecode(bbr, value) {
+		return bbr.Err()
 	}
 
-	if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
-		s.sliceArrayEncode(bbw, &value)
-		return bbw.Bytes()
+	if s.deserializePrimitive(bbr, &value) {
+		return bbr.Err()
 	}
 
-	if value.Kind() == reflect.Map {
-		s.mapEncode(bbw, &value)
-		return bbw.Bytes()
+	if value.Kind() == reflect.Interface {
+		s.interfaceDecodeInto(bbr, value)
+		return bbr.Err()
 	}
 
-	if value.Kind() == reflect.Chan {
-		return nil
+	switch value.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+		s.planOf(value.Type()).Decode(bbr, value)
 	}
 
-	return bbw.Bytes()
+	return bbr.Err()
 }
 
-func (s *RawBinarySerializer) decode(data []byte, target interface{}) int {
-	bbr := bytesx.NewReader(data)
+func (s *RawBinarySerializer) reflectDecode(bbr *bytesx.Reader, value reflect.Value) {
+	s.planOf(value.Type()).Decode(bbr, value)
+}
 
-	value := reflect.ValueOf(target)
-	if value.Kind() == reflect.Ptr {
-		value = value.Elem()
+// ################################################################################################################## \\
+// typed values
+// ################################################################################################################## \\
+
+// typedCodec returns the codec of the values of typ used by Encode, Decode and NewCodec, built once per type.
+func (s *RawBinarySerializer) typedCodec(typ reflect.Type) (*binaryx.TypedCodec, bool) {
+	if cached, ok := s.typed.Load(typ); ok {
+		tc := cached.(*binaryx.TypedCodec)
+		return tc, tc != nil
 	}
 
-	if s.deserializePrimitive(bbr, &value) {
-		return bbr.Yield()
+	var tc *binaryx.TypedCodec
+	if t, ok := binaryx.NewTyped(typ, s.planOf); ok {
+		tc = &binaryx.TypedCodec{
+			Encode: func(ptr unsafe.Pointer) ([]byte, error) {
+				return s.encodeTyped(t, ptr)
+			},
+			Decode: func(data []byte, ptr unsafe.Pointer) error {
+				return s.decodeTyped(t, data, ptr)
+			},
+		}
 	}
 
-	if value.Kind() == reflect.Struct {
-		s.structDecode(bbr, &value)
-		return bbr.Yield()
+	s.typed.Store(typ, tc)
+	return tc, tc != nil
+}
+
+// encodeTyped is Serialize for the value ptr points to, whose plan was resolved beforehand.
+func (s *RawBinarySerializer) encodeTyped(t *binaryx.Typed, ptr unsafe.Pointer) ([]byte, error) {
+	bbw := s.pool.Get()
+	defer s.pool.Put(bbw)
+
+	start := s.putHeader(bbw, t.Type)
+	if value, ok := t.Value(ptr); ok {
+		t.Plan().Encode(bbw, value)
+		if err := bbw.Err(); err != nil {
+			return nil, fmt.Errorf(models.EncodeErrMsg, err)
+		}
 	}
 
-	if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
-		s.sliceArrayDecode(bbr, &value)
-		return bbr.Yield()
+	return bytes.Clone(s.putTrailer(bbw, start)), nil
+}
+
+// decodeTyped is Deserialize into the value ptr points to, whose plan was resolved beforehand.
+func (s *RawBinarySerializer) decodeTyped(t *binaryx.Typed, data []byte, ptr unsafe.Pointer) error {
+	bbr := bytesx.NewReader(data)
+	d, err := s.open(bbr, t.Type)
+	if err == nil {
+		if d == s {
+			t.Plan().Decode(bbr, t.Target(ptr))
+			err = bbr.Err()
+		} else {
+			// the envelope asks for another layout than the one the plan was resolved for
+			err = d.decodeFrom(bbr, t.Target(ptr).Addr().Interface())
+		}
 	}
 
-	if value.Kind() == reflect.Map {
-		s.mapDecode(bbr, &value)
-		return bbr.Yield()
+	if err != nil {
+		return fmt.Errorf(models.DecodeErrMsg, err)
 	}
 
-	return bbr.Yield()
+	return nil
 }
 
-func (s *RawBinarySerializer) reflectDecode(data []byte, value reflect.Value) int {
-	bbr := bytesx.NewReader(data)
+// ################################################################################################################## \\
+// type plans
+// ################################################################################################################## \\
 
-	if value.Kind() == reflect.Ptr {
-		if bbr.Next() == 1 {
-			return bbr.Yield()
+// rawBinaryPlans holds the plans compiled by every RawBinarySerializer, per type and options.
+var rawBinaryPlans = binaryx.NewPlanCache()
+
+// planOf returns the plan of typ, compiling it the first time typ is met with the options of s.
+func (s *RawBinarySerializer) planOf(typ reflect.Type) *binaryx.Plan {
+	if p, ok := rawBinaryPlans.Load(typ, s.opts); ok {
+		return p
+	}
+
+	return rawBinaryPlans.Compile(typ, s.opts, s.compilePlan)
+}
+
+func (s *RawBinarySerializer) compilePlan(typ reflect.Type, planOf func(reflect.Type) *binaryx.Plan) binaryx.Plan {
+	return binaryx.Plan{
+		Encode: s.compileEncoder(typ, planOf),
+		Decode: s.compileDecoder(typ, planOf),
+	}
+}
+
+// compileEncoder picks the encoder of typ the way reflectEncode used to for every value: pointers first, then
+// codecs, marshalers and generated methods, and the kind of typ last.
+func (s *RawBinarySerializer) compileEncoder(typ reflect.Type, planOf func(reflect.Type) *binaryx.Plan) binaryx.EncodeFunc {
+	if typ.Kind() == reflect.Ptr {
+		elem := planOf(typ.Elem())
+		return func(bbw *bytesx.Writer, value reflect.Value) {
+			if value.IsNil() {
+				bbw.Put(1)
+				return
+			}
+
+			bbw.Put(0)
+			elem.Encode(bbw, value.Elem())
 		}
+	}
 
-		value.Set(reflect.New(value.Type().Elem()))
-		value = value.Elem()
+	if _, ok := binaryx.CodecOf(typ); ok {
+		return func(bbw *bytesx.Writer, value reflect.Value) {
+			s.codecEncode(bbw, value)
+		}
 	}
 
-	if s.deserializePrimitive(bbr, &value) {
-		return bbr.Yield()
+	if binaryx.MarshalerOf(typ) != binaryx.NoMarshaler {
+		return func(bbw *bytesx.Writer, value reflect.Value) {
+			s.marshalerEncode(bbw, value)
+		}
+	}
+
+	if codec, ok := binaryx.GeneratedCodecOf(typ); ok && s.opts.GeneratedLayout() {
+		return func(bbw *bytesx.Writer, value reflect.Value) {
+			if err := codec.Encode(bbw, value); err != nil && bbw.Err() == nil {
+				bbw.Fail(err)
+			}
+		}
+	}
+
+	switch typ.Kind() {
+	case reflect.Interface:
+		return func(bbw *bytesx.Writer, value reflect.Value) {
+			s.interfaceEncode(bbw, value.Interface())
+		}
+	case reflect.Struct:
+		return s.compileStructEncoder(typ, planOf)
+	case reflect.Slice:
+		return s.compileSliceEncoder(typ, planOf)
+	case reflect.Array:
+		return s.compileArrayEncoder(typ, planOf)
+	case reflect.Map:
+		return s.compileMapEncoder(typ, planOf)
+	default:
+		// primitives, unsupported kinds being rejected before reaching here
+		return func(bbw *bytesx.Writer, value reflect.Value) {
+			s.serializeReflectPrimitive(bbw, &value)
+		}
+	}
+}
+
+// compileDecoder picks the decoder of typ, mirroring compileEncoder.
+func (s *RawBinarySerializer) compileDecoder(typ reflect.Type, planOf func(reflect.Type) *binaryx.Plan) binaryx.DecodeFunc {
+	if typ.Kind() == reflect.Ptr {
+		elemType, elem := typ.Elem(), planOf(typ.Elem())
+		return func(bbr *bytesx.Reader, value reflect.Value) {
+			if bbr.Next() == 1 {
+				return
+			}
+
+			value.Set(reflect.New(elemType))
+			elem.Decode(bbr, value.Elem())
+		}
 	}
 
-	if value.Kind() == reflect.Struct {
-		s.structDecode(bbr, &value)
-		return bbr.Yield()
+	if _, ok := binaryx.CodecOf(typ); ok {
+		return func(bbr *bytesx.Reader, value reflect.Value) {
+			s.codecDecode(bbr, value)
+		}
 	}
 
-	if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
-		s.sliceArrayDecode(bbr, &value)
-		return bbr.Yield()
+	if binaryx.MarshalerOf(typ) != binaryx.NoMarshaler {
+		return func(bbr *bytesx.Reader, value reflect.Value) {
+			s.marshalerDecode(bbr, value)
+		}
 	}
 
-	if value.Kind() == reflect.Map {
-		s.mapDecode(bbr, &value)
-		return bbr.Yield()
+	if codec, ok := binaryx.GeneratedCodecOf(typ); ok && s.opts.GeneratedLayout() {
+		return func(bbr *bytesx.Reader, value reflect.Value) {
+			if err := codec.Decode(bbr, value); err != nil && bbr.Err() == nil {
+				bbr.Fail(err)
+			}
+		}
 	}
 
-	return bbr.Yield()
+	switch typ.Kind() {
+	case reflect.Interface:
+		return func(bbr *bytesx.Reader, value reflect.Value) {
+			s.interfaceDecodeInto(bbr, value)
+		}
+	case reflect.Struct:
+		return s.compileStructDecoder(typ, planOf)
+	case reflect.Slice:
+		return s.compileSliceDecoder(typ, planOf)
+	case reflect.Array:
+		return s.compileArrayDecoder(typ, planOf)
+	case reflect.Map:
+		return s.compileMapDecoder(typ, planOf)
+	default:
+		return func(bbr *bytesx.Reader, value reflect.Value) {
+			s.deserializePrimitive(bbr, &value)
+		}
+	}
 }
 
 // ################################################################################################################## \\
@@ -254,6 +517,10 @@ func (s *RawBinarySerializer) serializePrimitive(bbw *bytesx.Writer, data interf
 }
 
 func (s *RawBinarySerializer) serializeReflectPrimitive(bbw *bytesx.Writer, v *reflect.Value) bool {
+	if s.opts.Varint && s.serializeReflectVarint(bbw, v) {
+		return true
+	}
+
 	switch v.Kind() {
 	case reflect.Bool:
 		if v.Bool() {
@@ -310,68 +577,69 @@ func (s *RawBinarySerializer) serializeReflectPrimitive(bbw *bytesx.Writer, v *r
 		bbw.Write(bytesx.AddUint64(math.Float64bits(real(v.Complex()))))
 		bbw.Write(bytesx.AddUint64(math.Float64bits(imag(v.Complex()))))
 		return true
-	case reflect.Uintptr:
-		bbw.Write(bytesx.AddUint64(uint64(v.Int())))
-		return true
 	default:
 		return false
 	}
 }
 
 func (s *RawBinarySerializer) deserializePrimitive(bbr *bytesx.Reader, field *reflect.Value) bool {
+	if s.opts.Varint && s.deserializeVarint(bbr, field) {
+		return true
+	}
+
 	switch field.Kind() {
 	case reflect.Bool:
 		field.SetBool(bbr.Next() == 1)
 		return true
 	case reflect.String:
-		field.SetString(s.decodeUnsafeString(bbr))
+		field.SetString(s.decodeString(bbr))
 		return true
 	case reflect.Int:
-		field.SetInt(int64(bytesx.Uint64(bbr.Read(8))))
+		field.SetInt(int64(bbr.Uint64()))
 		return true
 	case reflect.Int8:
 		field.SetInt(int64(bbr.Next()))
 		return true
 	case reflect.Int16:
-		field.SetInt(int64(bytesx.Uint16(bbr.Read(2))))
+		field.SetInt(int64(bbr.Uint16()))
 		return true
 	case reflect.Int32:
-		field.SetInt(int64(bytesx.Uint32(bbr.Read(4))))
+		field.SetInt(int64(bbr.Uint32()))
 		return true
 	case reflect.Int64:
-		field.SetInt(int64(bytesx.Uint64(bbr.Read(8))))
+		field.SetInt(int64(bbr.Uint64()))
 		return true
 	case reflect.Uint:
-		field.SetUint(bytesx.Uint64(bbr.Read(8)))
+		field.SetUint(bbr.Uint64())
 		return true
 	case reflect.Uint8:
 		field.SetUint(uint64(bbr.Next()))
 		return true
 	case reflect.Uint16:
-		field.SetUint(uint64(bytesx.Uint16(bbr.Read(2))))
+		field.SetUint(uint64(bbr.Uint16()))
 		return true
 	case reflect.Uint32:
-		field.SetUint(uint64(bytesx.Uint32(bbr.Read(4))))
+		field.SetUint(uint64(bbr.Uint32()))
 		return true
 	case reflect.Uint64:
-		field.SetUint(bytesx.Uint64(bbr.Read(8)))
+		field.SetUint(bbr.Uint64())
 		return true
 	case reflect.Float32:
-		field.SetFloat(float64(math.Float32frombits(bytesx.Uint32(bbr.Read(4)))))
+		field.SetFloat(float64(math.Float32frombits(bbr.Uint32())))
 		return true
 	case reflect.Float64:
-		field.SetFloat(math.Float64frombits(bytesx.Uint64(bbr.Read(8))))
+		field.SetFloat(math.Float64frombits(bbr.Uint64()))
 		return true
 	case reflect.Complex64:
 		field.SetComplex(complex(
-			float64(math.Float32frombits(bytesx.Uint32(bbr.Read(4)))),
-			float64(math.Float32frombits(bytesx.Uint32(bbr.Read(4)))),
+			float64(math.Float32frombits(bbr.Uint32())),
+			float64(math.Float32frombits(bbr.Uint32())),
 		))
 		return true
 	case reflect.Complex128:
 		field.SetComplex(complex(
-			math.Float64frombits(bytesx.Uint64(bbr.Read(8))),
-			math.Float64frombits(bytesx.Uint64(bbr.Read(8))),
+			math.Float64frombits(bbr.Uint64()),
+			math.Float64frombits(bbr.Uint64()),
 		))
 		return true
 	default:
@@ -486,7 +754,7 @@ func (s *RawBinarySerializer) serializePrimitiveSliceArray(bbw *bytesx.Writer, d
 	case [][]byte:
 		for _, bs := range v {
 			size := len(bs)
-			bbw.Write(bytesx.AddUint32(uint32(size)))
+			s.putLength(bbw, size)
 			if size == 0 {
 				if bs == nil {
 					bbw.Put(1)
@@ -509,7 +777,11 @@ func (s *RawBinarySerializer) serializePrimitiveSliceArray(bbw *bytesx.Writer, d
 func (s *RawBinarySerializer) serializeReflectPrimitiveSliceArray(
 	bbw *bytesx.Writer, field *reflect.Value, length int,
 ) bool {
-	switch field.Type().String() {
+	if s.opts.Varint && s.serializeVarintSliceArray(bbw, field, length) {
+		return true
+	}
+
+	switch binaryx.PrimitiveSlice(field.Type()) {
 	case "[]bool":
 		for i := 0; i < length; i++ {
 			if field.Index(i).Bool() {
@@ -606,7 +878,7 @@ func (s *RawBinarySerializer) serializeReflectPrimitiveSliceArray(
 		for i := 0; i < length; i++ {
 			f := field.Index(i)
 			size := f.Len()
-			bbw.Write(bytesx.AddUint32(uint32(size)))
+			s.putLength(bbw, size)
 			if size == 0 {
 				continue
 			}
@@ -623,74 +895,126 @@ func (s *RawBinarySerializer) serializeReflectPrimitiveSliceArray(
 func (s *RawBinarySerializer) deserializeReflectPrimitiveSliceArray(
 	bbr *bytesx.Reader, field *reflect.Value, length int,
 ) bool {
-	switch field.Type().String() {
+	if s.opts.Varint && s.deserializeVarintSliceArray(bbr, field, length) {
+		return true
+	}
+
+	switch binaryx.PrimitiveSlice(field.Type()) {
 	case "[]bool":
+		if !bbr.Ensure(length) {
+			return true
+		}
+
 		bb := make([]bool, length)
 		for i := range bb {
 			bb[i] = bbr.Next() == 1
 		}
 
-		field.Set(reflect.ValueOf(bb))
+		binaryx.SetSlice(*field, reflect.ValueOf(bb))
 		return true
 	case "[]string":
+		if !bbr.Ensure(length * s.minWireSize(4)) {
+			return true
+		}
+
 		ss := make([]string, length)
 		for i := range ss {
-			ss[i] = s.decodeUnsafeString(bbr)
+			ss[i] = s.decodeString(bbr)
 		}
 
-		field.Set(reflect.ValueOf(ss))
+		binaryx.SetSlice(*field, reflect.ValueOf(ss))
 		return true
 	case "[]int":
+		if !bbr.Ensure(length * 8) {
+			return true
+		}
+
 		*(*[]int64)(unsafe.Pointer(field.UnsafeAddr())) =
-			unsafe.Slice((*int64)(unsafe.Pointer(&bbr.Read(length * 8)[0])), length)
+			unsafe.Slice((*int64)(unsafe.Pointer(&s.readBytes(bbr, length*8)[0])), length)
 		return true
 	case "[]int8":
+		if !bbr.Ensure(length) {
+			return true
+		}
+
 		*(*[]int8)(unsafe.Pointer(field.UnsafeAddr())) =
-			unsafe.Slice((*int8)(unsafe.Pointer(&bbr.Read(length)[0])), length)
+			unsafe.Slice((*int8)(unsafe.Pointer(&s.readBytes(bbr, length)[0])), length)
 		return true
 	case "[]int16":
+		if !bbr.Ensure(length * 2) {
+			return true
+		}
+
 		*(*[]int16)(unsafe.Pointer(field.UnsafeAddr())) =
-			unsafe.Slice((*int16)(unsafe.Pointer(&bbr.Read(length * 2)[0])), length)
+			unsafe.Slice((*int16)(unsafe.Pointer(&s.readBytes(bbr, length*2)[0])), length)
 		return true
 	case "[]int32":
+		if !bbr.Ensure(length * 4) {
+			return true
+		}
+
 		*(*[]int32)(unsafe.Pointer(field.UnsafeAddr())) =
-			unsafe.Slice((*int32)(unsafe.Pointer(&bbr.Read(length * 4)[0])), length)
+			unsafe.Slice((*int32)(unsafe.Pointer(&s.readBytes(bbr, length*4)[0])), length)
 		return true
 	case "[]int64":
+		if !bbr.Ensure(length * 8) {
+			return true
+		}
+
 		*(*[]int64)(unsafe.Pointer(field.UnsafeAddr())) =
-			unsafe.Slice((*int64)(unsafe.Pointer(&bbr.Read(length * 8)[0])), length)
+			unsafe.Slice((*int64)(unsafe.Pointer(&s.readBytes(bbr, length*8)[0])), length)
 		return true
 	case "[]uint":
+		if !bbr.Ensure(length * 8) {
+			return true
+		}
+
 		*(*[]uint64)(unsafe.Pointer(field.UnsafeAddr())) =
-			unsafe.Slice((*uint64)(unsafe.Pointer(&bbr.Read(length * 8)[0])), length)
+			unsafe.Slice((*uint64)(unsafe.Pointer(&s.readBytes(bbr, length*8)[0])), length)
 		return true
 	case "[]uint8":
-		field.SetBytes(bbr.Read(length))
+		field.SetBytes(s.readBytes(bbr, length))
 		return true
 	case "[]uint16":
+		if !bbr.Ensure(length * 2) {
+			return true
+		}
+
 		*(*[]uint16)(unsafe.Pointer(field.UnsafeAddr())) =
-			unsafe.Slice((*uint16)(unsafe.Pointer(&bbr.Read(length * 2)[0])), length)
+			unsafe.Slice((*uint16)(unsafe.Pointer(&s.readBytes(bbr, length*2)[0])), length)
 		return true
 	case "[]uint32":
+		if !bbr.Ensure(length * 4) {
+			return true
+		}
+
 		*(*[]uint32)(unsafe.Pointer(field.UnsafeAddr())) =
-			unsafe.Slice((*uint32)(unsafe.Pointer(&bbr.Read(length * 4)[0])), length)
+			unsafe.Slice((*uint32)(unsafe.Pointer(&s.readBytes(bbr, length*4)[0])), length)
 		return true
 	case "[]uint64":
+		if !bbr.Ensure(length * 8) {
+			return true
+		}
+
 		*(*[]uint64)(unsafe.Pointer(field.UnsafeAddr())) =
-			unsafe.Slice((*uint64)(unsafe.Pointer(&bbr.Read(length * 8)[0])), length)
+			unsafe.Slice((*uint64)(unsafe.Pointer(&s.readBytes(bbr, length*8)[0])), length)
 		return true
 	case "[][]uint8":
+		if !bbr.Ensure(length * s.minWireSize(4)) {
+			return true
+		}
+
 		ii := make([][]byte, length)
 		for i := range ii {
-			l := int(bytesx.Uint32(bbr.Read(4)))
+			l := s.readLength(bbr)
 			if l == 0 {
 				continue
 			}
 
-			ii[i] = bbr.Read(l)
+			ii[i] = s.readBytes(bbr, l)
 		}
 
-		field.Set(reflect.ValueOf(ii))
+		binaryx.SetSlice(*field, reflect.ValueOf(ii))
 		return true
 	default:
 		return false
@@ -701,328 +1025,968 @@ func (s *RawBinarySerializer) deserializeReflectPrimitiveSliceArray(
 // struct encoder
 // ################################################################################################################## \\
 
-func (s *RawBinarySerializer) structEncode(bbw *bytesx.Writer, field *reflect.Value) {
-	limit := field.NumField()
-	for idx := 0; idx < limit; idx++ {
-		f := field.Field(idx)
-
-		if f.Kind() == reflect.Ptr {
-			if f.IsNil() {
-				bbw.Put(1)
-
-				continue
-			}
-
-			bbw.Put(0)
-			f = f.Elem()
-		}
-
-		if f.Kind() == reflect.Struct {
-			//bbw.Write(s.encode(f.Interface()))
-			bbw.Write(s.reflectEncode(f))
-			continue
+// compileStructEncoder compiles the encoder of the struct type typ, resolving its fields once.
+func (s *RawBinarySerializer) compileStructEncoder(
+	typ reflect.Type, planOf func(reflect.Type) *binaryx.Plan,
+) binaryx.EncodeFunc {
+	fields, err := binaryx.FieldPlans(typ, s.opts, planOf)
+	if err != nil {
+		return func(bbw *bytesx.Writer, _ reflect.Value) {
+			bbw.Fail(err)
 		}
+	}
 
-		if f.Kind() == reflect.Slice || f.Kind() == reflect.Array {
-			s.sliceArrayEncode(bbw, &f)
-			continue
-		}
+	encodeFields := s.structEncode
+	if s.opts.FieldIDs {
+		encodeFields = s.structEncodeFieldIDs
+	}
 
-		if f.Kind() == reflect.Map {
-			s.mapEncode(bbw, &f)
-			continue
+	// unexported fields can only be reached through their address
+	copyUnaddressable := s.opts.UnexportedFields == binaryx.IncludeUnexported
+	return func(bbw *bytesx.Writer, value reflect.Value) {
+		if copyUnaddressable && !value.CanAddr() {
+			addressable := reflect.New(typ).Elem()
+			addressable.Set(value)
+			value = addressable
 		}
 
-		s.serializeReflectPrimitive(bbw, &f)
+		encodeFields(bbw, value, fields)
 	}
 }
 
-func (s *RawBinarySerializer) structDecode(bbr *bytesx.Reader, field *reflect.Value) {
-	limit := field.NumField()
-	for idx := 0; idx < limit; idx++ {
-		f := field.Field(idx)
-
-		if f.Kind() == reflect.Ptr {
-			if bbr.Next() == 1 {
-				continue
-			}
-
-			f.Set(reflect.New(f.Type().Elem()))
-			f = f.Elem()
+func (s *RawBinarySerializer) structEncode(bbw *bytesx.Writer, field reflect.Value, fields []binaryx.FieldPlan) {
+	for i := range fields {
+		sf := &fields[i]
+		if sf.Plan == nil {
+			bbw.Fail(&models.UnsupportedTypeError{Field: sf.Name, Type: sf.Type, Kind: sf.Unsupported})
+			return
 		}
 
-		if f.Kind() == reflect.Struct {
-			bbr.Skip(s.reflectDecode(bbr.BytesFromCursor(), f))
-			continue
-		}
-
-		if f.Kind() == reflect.Slice || f.Kind() == reflect.Array {
-			s.sliceArrayDecode(bbr, &f)
-			continue
+		f := field.Field(sf.Index)
+		if !sf.Exported {
+			f = reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
 		}
 
-		if f.Kind() == reflect.Map {
-			s.mapDecode(bbr, &f)
-			continue
+		sf.Plan.Encode(bbw, f)
+		if bbw.Err() != nil {
+			bbw.AnnotateField(sf.Name)
+			return
 		}
-
-		s.deserializePrimitive(bbr, &f)
 	}
 }
 
-// ################################################################################################################## \\
-// slice & array encoder
-// ################################################################################################################## \\
+// compileStructDecoder compiles the decoder of the struct type typ, resolving its fields once.
+func (s *RawBinarySerializer) compileStructDecoder(
+	typ reflect.Type, planOf func(reflect.Type) *binaryx.Plan,
+) binaryx.DecodeFunc {
+	fields, err := binaryx.FieldPlans(typ, s.opts, planOf)
+	if err != nil {
+		return func(bbr *bytesx.Reader, _ reflect.Value) {
+			bbr.Fail(err)
+		}
+	}
 
-func (s *RawBinarySerializer) sliceArrayEncode(bbw *bytesx.Writer, field *reflect.Value) {
-	fLen := field.Len()
-	bbw.Write(bytesx.AddUint32(uint32(fLen)))
-	if fLen == 0 {
-		return
+	if s.opts.FieldIDs {
+		return func(bbr *bytesx.Reader, value reflect.Value) {
+			s.structDecodeFieldIDs(bbr, value, fields)
+		}
 	}
 
-	//if s.serializePrimitiveSliceArray(bbw, field.Interface()) {
-	//	return
-	//}
-	if s.serializeReflectPrimitiveSliceArray(bbw, field, fLen) {
-		return
+	return func(bbr *bytesx.Reader, value reflect.Value) {
+		s.structDecode(bbr, value, fields)
 	}
+}
 
-	for i := 0; i < fLen; i++ {
-		f := field.Index(i)
+func (s *RawBinarySerializer) structDecode(bbr *bytesx.Reader, field reflect.Value, fields []binaryx.FieldPlan) {
+	for i := range fields {
+		sf := &fields[i]
+		if sf.Plan == nil {
+			bbr.Fail(&models.UnsupportedTypeError{Field: sf.Name, Type: sf.Type, Kind: sf.Unsupported})
+			return
+		}
 
-		if f.Kind() == reflect.Ptr {
-			if f.IsNil() {
-				bbw.Put(1)
-				continue
-			}
+		f := field.Field(sf.Index)
+		if !sf.Exported {
+			f = reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
+		}
 
-			bbw.Put(0)
-			f = f.Elem()
+		sf.Plan.Decode(bbr, f)
+		if bbr.Err() != nil {
+			bbr.AnnotateField(sf.Name, sf.Type.String())
+			return
 		}
+	}
+}
 
-		if f.Kind() == reflect.Struct {
-			s.structEncode(bbw, &f)
-			continue
+// structEncodeFieldIDs writes the fields of a struct in the field id mode, each one preceded by its key.
+// Fields of the bytes wire type are prefixed with their length so that decoders unaware of them can skip them.
+func (s *RawBinarySerializer) structEncodeFieldIDs(bbw *bytesx.Writer, field reflect.Value, fields []binaryx.FieldPlan) {
+	for i := range fields {
+		sf := &fields[i]
+		if sf.Plan == nil {
+			bbw.Fail(&models.UnsupportedTypeError{Field: sf.Name, Type: sf.Type, Kind: sf.Unsupported})
+			return
 		}
 
-		if f.Kind() == reflect.Slice || f.Kind() == reflect.Array {
-			bbw.Write(s.reflectEncode(f))
-			continue
+		if sf.ID == 0 {
+			bbw.Fail(binaryx.MissingFieldIDError(field.Type(), sf.Field))
+			return
 		}
 
-		if f.Kind() == reflect.Map {
-			s.mapEncode(bbw, &f)
-			continue
+		f := field.Field(sf.Index)
+		if !sf.Exported {
+			f = reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
 		}
 
-		// this is always a primitive
-		s.serializeReflectPrimitive(bbw, &f)
-		continue
-	}
-}
+		binaryx.PutFieldKey(bbw, sf.ID, sf.WireType)
+		if sf.WireType != binaryx.WireBytes {
+			sf.Plan.Encode(bbw, f)
+		} else {
+			offset := bbw.Len()
+			bbw.Write(bytesx.AddUint32(0))
+			sf.Plan.Encode(bbw, f)
+			bbw.PatchUint32(offset, uint32(bbw.Len()-offset-4))
+		}
 
-func (s *RawBinarySerializer) sliceArrayDecode(bbr *bytesx.Reader, field *reflect.Value) {
-	length := int(bytesx.Uint32(bbr.Read(4)))
-	if length == 0 {
-		// field.Set(reflect.MakeSlice(field.Type(), 0, length))
-		return
+		if bbw.Err() != nil {
+			bbw.AnnotateField(sf.Name)
+			return
+		}
 	}
 
-	if s.deserializeReflectPrimitiveSliceArray(bbr, field, length) {
-		return
-	}
+	binaryx.PutEndOfFields(bbw)
+}
 
-	field.Set(reflect.MakeSlice(field.Type(), length, length))
-	for i := 0; i < length; i++ {
-		f := field.Index(i)
+// structDecodeFieldIDs reads the fields written by structEncodeFieldIDs. Unknown fields are skipped and the ones
+// missing from the payload are left zero.
+func (s *RawBinarySerializer) structDecodeFieldIDs(bbr *bytesx.Reader, field reflect.Value, fields []binaryx.FieldPlan) {
+	binaryx.ZeroFields(field, fields)
 
-		if s.deserializePrimitive(bbr, &f) {
-			continue
+	for {
+		offset := bbr.Yield()
+		id, wt := binaryx.ReadFieldKey(bbr)
+		if id == 0 || bbr.Err() != nil {
+			return
 		}
 
-		if f.Kind() == reflect.Slice || f.Kind() == reflect.Array {
-			bbr.Skip(s.reflectDecode(bbr.BytesFromCursor(), f))
+		sf := binaryx.FieldPlanByID(fields, id)
+		if sf == nil {
+			binaryx.SkipField(bbr, wt)
 			continue
 		}
 
-		if f.Kind() == reflect.Ptr {
-			if bbr.Next() == 1 {
-				continue
-			}
+		if sf.Plan == nil {
+			bbr.Fail(&models.UnsupportedTypeError{Field: sf.Name, Type: sf.Type, Kind: sf.Unsupported})
+			return
+		}
 
-			f.Set(reflect.New(f.Type().Elem()))
-			f = f.Elem()
+		if wt != sf.WireType {
+			bbr.Fail(&models.DecodeError{
+				Field:  sf.Name,
+				Type:   sf.Type.String(),
+				Offset: offset,
+				Reason: fmt.Sprintf("field id %d has wire type %s, want %s", id, wt, sf.WireType),
+			})
+			return
 		}
 
-		if f.Kind() == reflect.Struct {
-			s.structDecode(bbr, &f)
-			continue
+		f := field.Field(sf.Index)
+		if !sf.Exported {
+			f = reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
 		}
 
-		if f.Kind() == reflect.Map {
-			s.mapDecode(bbr, &f)
-			continue
+		if wt != binaryx.WireBytes {
+			sf.Plan.Decode(bbr, f)
+		} else {
+			length := int(bbr.Uint32())
+			start := bbr.Yield()
+			if bbr.Ensure(length) {
+				sf.Plan.Decode(bbr, f)
+			}
+
+			if read := bbr.Yield() - start; bbr.Err() == nil && read != length {
+				bbr.Fail(&models.DecodeError{
+					Offset: start,
+					Reason: fmt.Sprintf("field length is %d but %d bytes were read", length, read),
+				})
+			}
 		}
 
-		// this is always a primitive
-		s.deserializePrimitive(bbr, &f)
+		if bbr.Err() != nil {
+			bbr.AnnotateField(sf.Name, sf.Type.String())
+			return
+		}
 	}
 }
 
 // ################################################################################################################## \\
-// map encoder
+// slice & array encoder
 // ################################################################################################################## \\
 
-func (s *RawBinarySerializer) mapEncode(bbw *bytesx.Writer, field *reflect.Value) {
-	fLen := field.Len()
-	bbw.Write(bytesx.AddUint32(uint32(fLen)))
+// compileSliceEncoder compiles the encoder of the slice type typ. Slices of primitives take the bulk paths of
+// sliceArrayEncode, the other ones encode their elements with the plan of the element type.
+func (s *RawBinarySerializer) compileSliceEncoder(typ reflect.Type, planOf func(reflect.Type) *binaryx.Plan) binaryx.EncodeFunc {
+	if binaryx.PrimitiveSlice(typ) != "" {
+		return func(bbw *bytesx.Writer, value reflect.Value) {
+			s.sliceArrayEncode(bbw, &value)
+		}
+	}
 
-	if fLen == 0 {
-		return
+	elem := planOf(typ.Elem())
+	return func(bbw *bytesx.Writer, value reflect.Value) {
+		fLen := value.Len()
+		s.putLength(bbw, fLen)
+		s.elemsEncode(bbw, value, fLen, elem)
 	}
+}
 
-	switch rawFieldValue := field.Interface().(type) {
-	case map[int]int:
-		for k, v := range rawFieldValue {
-			bbw.Write(bytesx.AddUint64(uint64(k)))
-			bbw.Write(bytesx.AddUint64(uint64(v)))
+// compileSliceDecoder compiles the decoder of the slice type typ. Slices of primitives without a bulk path, such as
+// []float64, are decoded element by element like the other ones.
+func (s *RawBinarySerializer) compileSliceDecoder(typ reflect.Type, planOf func(reflect.Type) *binaryx.Plan) binaryx.DecodeFunc {
+	primitive := binaryx.PrimitiveSlice(typ) != ""
+	elem, elemType := planOf(typ.Elem()), typ.Elem().String()
+	elemSize := typ.Elem().Size()
+	return func(bbr *bytesx.Reader, value reflect.Value) {
+		length := s.readLength(bbr)
+		if length == 0 || (primitive && s.deserializeReflectPrimitiveSliceArray(bbr, &value, length)) {
+			return
 		}
 
-		return
-	case map[int64]int64:
-		for k, v := range rawFieldValue {
-			bbw.Write(bytesx.AddUint64(uint64(k)))
-			bbw.Write(bytesx.AddUint64(uint64(v)))
+		if !binaryx.EnsureLength(bbr, length, elemSize) {
+			return
 		}
 
-		return
-	case map[string]string:
-		for k, v := range rawFieldValue {
-			s.encodeUnsafeString(bbw, k)
-			s.encodeUnsafeString(bbw, v)
-		}
+		value.Set(reflect.MakeSlice(typ, length, length))
+		s.elemsDecode(bbr, value, length, elem, elemType)
+	}
+}
+
+// compileArrayEncoder compiles the encoder of the array type typ, mirroring compileSliceEncoder.
+func (s *RawBinarySerializer) compileArrayEncoder(typ reflect.Type, planOf func(reflect.Type) *binaryx.Plan) binaryx.EncodeFunc {
+	if binaryx.PrimitiveSlice(reflect.SliceOf(typ.Elem())) != "" {
+		return func(bbw *bytesx.Writer, value reflect.Value) {
+			s.arrayEncode(bbw, &value)
+		}
+	}
 
+	elem, length := planOf(typ.Elem()), typ.Len()
+	return func(bbw *bytesx.Writer, value reflect.Value) {
+		s.elemsEncode(bbw, value, length, elem)
+	}
+}
+
+// compileArrayDecoder compiles the decoder of the array type typ, falling back like compileSliceDecoder.
+func (s *RawBinarySerializer) compileArrayDecoder(typ reflect.Type, planOf func(reflect.Type) *binaryx.Plan) binaryx.DecodeFunc {
+	primitive := binaryx.PrimitiveSlice(reflect.SliceOf(typ.Elem())) != ""
+	elem, elemType, length := planOf(typ.Elem()), typ.Elem().String(), typ.Len()
+	return func(bbr *bytesx.Reader, value reflect.Value) {
+		if length == 0 || (primitive && s.arrayDecode(bbr, &value)) {
+			return
+		}
+
+		s.elemsDecode(bbr, value, length, elem, elemType)
+	}
+}
+
+// elemsEncode writes the first length elements of the slice or array field with the plan of their type.
+func (s *RawBinarySerializer) elemsEncode(bbw *bytesx.Writer, field reflect.Value, length int, elem *binaryx.Plan) {
+	for i := 0; i < length; i++ {
+		elem.Encode(bbw, field.Index(i))
+		if bbw.Err() != nil {
+			bbw.AnnotateField("[" + strconv.Itoa(i) + "]")
+			return
+		}
+	}
+}
+
+// elemsDecode reads the first length elements of the slice or array field with the plan of their type.
+func (s *RawBinarySerializer) elemsDecode(
+	bbr *bytesx.Reader, field reflect.Value, length int, elem *binaryx.Plan, elemType string,
+) {
+	for i := 0; i < length; i++ {
+		elem.Decode(bbr, field.Index(i))
+		if bbr.Err() != nil {
+			bbr.AnnotateField("["+strconv.Itoa(i)+"]", elemType)
+			return
+		}
+	}
+}
+
+// sliceArrayEncode writes the slices of primitives, the other ones being compiled by compileSliceEncoder.
+func (s *RawBinarySerializer) sliceArrayEncode(bbw *bytesx.Writer, field *reflect.Value) {
+	fLen := field.Len()
+	s.putLength(bbw, fLen)
+	if fLen == 0 {
 		return
+	}
 
-	// TODO: implement these map types
-	//case map[int]interface{}:
-	//	for k, v := range rawFieldValue {
-	//		bbw.Write(bytesx.AddUint64(uint64(k)))
-	//		bbw.Write(s.encode(v))
-	//	}
-	//
-	//	return
-	//case map[int64]interface{}:
-	//	for k, v := range rawFieldValue {
-	//		bbw.Write(bytesx.AddUint64(uint64(k)))
-	//		bbw.Write(s.encode(v))
-	//	}
-	//
-	//	return
-	//case map[string]interface{}:
-	//	for k, v := range rawFieldValue {
-	//		s.encodeUnsafeString(bbw, k)
-	//		bbw.Write(s.encode(v))
-	//	}
-	//
+	//if s.serializePrimitiveSliceArray(bbw, field.Interface()) {
 	//	return
-	//case map[interface{}]interface{}:
-	//	for k, v := range rawFieldValue {
-	//		bbw.Write(s.encode(k))
-	//		bbw.Write(s.encode(v))
-	//	}
-	default:
-		for _, key := range field.MapKeys() {
-			// key
-			bbw.Write(s.reflectEncode(key))
+	//}
+	s.serializeReflectPrimitiveSliceArray(bbw, field, fLen)
+}
+
+// arrayEncode writes the elements of a fixed-size array of primitives. The length is part of the type, so no
+// length prefix is written.
+func (s *RawBinarySerializer) arrayEncode(bbw *bytesx.Writer, field *reflect.Value) {
+	fLen := field.Len()
+	if fLen == 0 {
+		return
+	}
+
+	if !field.CanAddr() {
+		// slicing the array requires it to be addressable
+		addressable := reflect.New(field.Type()).Elem()
+		addressable.Set(*field)
+		field = &addressable
+	}
+
+	// arrays of primitives share the bulk paths of slices through a slice over their elements
+	elems := reflect.New(reflect.SliceOf(field.Type().Elem())).Elem()
+	elems.Set(field.Slice(0, fLen))
+	s.serializeReflectPrimitiveSliceArray(bbw, &elems, fLen)
+}
+
+// arrayDecode reads the arrays written by arrayEncode, decoding the elements in place.
+// It reports false for the array types without a bulk path.
+func (s *RawBinarySerializer) arrayDecode(bbr *bytesx.Reader, field *reflect.Value) bool {
+	elems := reflect.New(reflect.SliceOf(field.Type().Elem())).Elem()
+	if !s.deserializeReflectPrimitiveSliceArray(bbr, &elems, field.Len()) {
+		return false
+	}
+
+	if bbr.Err() == nil {
+		// copying also detaches the array from the payload the bulk paths may alias
+		reflect.Copy(*field, elems)
+	}
+
+	return true
+}
+
+// ################################################################################################################## \\
+// map encoder
+// ################################################################################################################## \\
+
+// compileMapEncoder compiles the encoder of the map type typ. The map types with a specialised path take it through
+// mapEncode, the other ones encode their entries with the plans of the key and element types.
+func (s *RawBinarySerializer) compileMapEncoder(typ reflect.Type, planOf func(reflect.Type) *binaryx.Plan) binaryx.EncodeFunc {
+	if binaryx.SpecialisedMap(typ) {
+		return func(bbw *bytesx.Writer, value reflect.Value) {
+			s.mapEncode(bbw, &value)
+		}
+	}
+
+	keyType, elemType := typ.Key(), typ.Elem()
+	key, elem := planOf(keyType), planOf(elemType)
+	return func(bbw *bytesx.Writer, value reflect.Value) {
+		fLen := value.Len()
+		s.putLength(bbw, fLen)
+		if fLen == 0 {
+			return
+		}
+
+		// the entries are copied into the same values rather than into new ones for each of them
+		keyValue, elemValue := reflect.New(keyType).Elem(), reflect.New(elemType).Elem()
+		iter := value.MapRange()
+		for iter.Next() {
+			keyValue.SetIterKey(iter)
+			elemValue.SetIterValue(iter)
+			key.Encode(bbw, keyValue)
+			elem.Encode(bbw, elemValue)
+			if bbw.Err() != nil {
+				bbw.AnnotateField(fmt.Sprintf("[%v]", keyValue.Interface()))
+				return
+			}
+		}
+	}
+}
+
+// compileMapDecoder compiles the decoder of the map type typ, mirroring compileMapEncoder.
+func (s *RawBinarySerializer) compileMapDecoder(typ reflect.Type, planOf func(reflect.Type) *binaryx.Plan) binaryx.DecodeFunc {
+	if binaryx.SpecialisedMap(typ) {
+		return func(bbr *bytesx.Reader, value reflect.Value) {
+			s.mapDecode(bbr, &value)
+		}
+	}
+
+	keyType, elemType := typ.Key(), typ.Elem()
+	key, elem := planOf(keyType), planOf(elemType)
+	keyTypeName, elemTypeName := keyType.String(), elemType.String()
+	entrySize := keyType.Size() + elemType.Size()
+	return func(bbr *bytesx.Reader, value reflect.Value) {
+		length := s.readLength(bbr)
+		if length == 0 || !binaryx.EnsureLength(bbr, length, entrySize) {
+			return
+		}
+
+		value.Set(reflect.MakeMapWithSize(typ, length))
+
+		// SetMapIndex copies the entries, so the same ones are reused for all of them
+		keyValue, elemValue := reflect.New(keyType).Elem(), reflect.New(elemType).Elem()
+		for i := 0; i < length; i++ {
+			keyValue.SetZero()
+			key.Decode(bbr, keyValue)
+			if bbr.Err() != nil {
+				bbr.AnnotateField("["+strconv.Itoa(i)+"]", keyTypeName)
+				return
+			}
+
+			elemValue.SetZero()
+			elem.Decode(bbr, elemValue)
+			if bbr.Err() != nil {
+				bbr.AnnotateField(fmt.Sprintf("[%v]", keyValue.Interface()), elemTypeName)
+				return
+			}
+
+			value.SetMapIndex(keyValue, elemValue)
+		}
+	}
+}
+
+// mapEncode writes the map types reported by binaryx.SpecialisedMap without going through reflection.
+func (s *RawBinarySerializer) mapEncode(bbw *bytesx.Writer, field *reflect.Value) {
+	fLen := field.Len()
+	s.putLength(bbw, fLen)
+
+	if fLen == 0 {
+		return
+	}
 
-			// value type
-			value := field.MapIndex(key)
-			// value
-			bbw.Write(s.reflectEncode(value))
+	switch rawFieldValue := field.Interface().(type) {
+	case map[int]int:
+		for k, v := range rawFieldValue {
+			s.putInt(bbw, int64(k))
+			s.putInt(bbw, int64(v))
+		}
+
+		return
+	case map[int64]int64:
+		for k, v := range rawFieldValue {
+			s.putInt(bbw, int64(k))
+			s.putInt(bbw, int64(v))
 		}
+
+		return
+	case map[string]string:
+		for k, v := range rawFieldValue {
+			s.encodeUnsafeString(bbw, k)
+			s.encodeUnsafeString(bbw, v)
+		}
+
+		return
+
+	case map[int]interface{}:
+		for k, v := range rawFieldValue {
+			s.putInt(bbw, int64(k))
+			s.interfaceEncode(bbw, v)
+			if bbw.Err() != nil {
+				bbw.AnnotateField(fmt.Sprintf("[%v]", k))
+				return
+			}
+		}
+
+		return
+	case map[int64]interface{}:
+		for k, v := range rawFieldValue {
+			s.putInt(bbw, int64(k))
+			s.interfaceEncode(bbw, v)
+			if bbw.Err() != nil {
+				bbw.AnnotateField(fmt.Sprintf("[%v]", k))
+				return
+			}
+		}
+
+		return
+	case map[string]interface{}:
+		for k, v := range rawFieldValue {
+			s.encodeUnsafeString(bbw, k)
+			s.interfaceEncode(bbw, v)
+			if bbw.Err() != nil {
+				bbw.AnnotateField(fmt.Sprintf("[%v]", k))
+				return
+			}
+		}
+
+		return
+	case map[interface{}]interface{}:
+		for k, v := range rawFieldValue {
+			s.interfaceEncode(bbw, k)
+			s.interfaceEncode(bbw, v)
+			if bbw.Err() != nil {
+				bbw.AnnotateField(fmt.Sprintf("[%v]", k))
+				return
+			}
+		}
+
+		return
 	}
 }
 
+// mapDecode reads the maps written by mapEncode.
 func (s *RawBinarySerializer) mapDecode(bbr *bytesx.Reader, field *reflect.Value) {
-	length := int(bytesx.Uint32(bbr.Read(4)))
+	length := s.readLength(bbr)
 	if length == 0 {
 		return
 	}
 
 	switch field.Interface().(type) {
 	case map[int]int:
+		if !bbr.Ensure(length * s.minWireSize(16)) {
+			return
+		}
+
 		tmtd := make(map[int]int, length)
 		for i := 0; i < length; i++ {
-			tmtd[int(bytesx.Uint64(bbr.Read(8)))] = int(bytesx.Uint64(bbr.Read(8)))
+			tmtd[int(s.readInt(bbr))] = int(s.readInt(bbr))
 		}
 		field.Set(reflect.ValueOf(tmtd))
 		return
 	case map[int64]int64:
+		if !bbr.Ensure(length * s.minWireSize(16)) {
+			return
+		}
+
 		tmtd := make(map[int64]int64, length)
 		for i := 0; i < length; i++ {
-			tmtd[int64(bytesx.Uint64(bbr.Read(8)))] = int64(bytesx.Uint64(bbr.Read(8)))
+			tmtd[s.readInt(bbr)] = s.readInt(bbr)
 		}
 		field.Set(reflect.ValueOf(tmtd))
 		return
 	case map[string]string:
+		if !bbr.Ensure(length * s.minWireSize(8)) {
+			return
+		}
+
 		tmtd := make(map[string]string, length)
 		for i := 0; i < length; i++ {
-			tmtd[s.decodeUnsafeString(bbr)] = s.decodeUnsafeString(bbr)
+			tmtd[s.decodeString(bbr)] = s.decodeString(bbr)
 		}
 		field.Set(reflect.ValueOf(tmtd))
 		return
 
-	// TODO: implement these map types
-	//case map[int]interface{}:
-	//	tmtd := make(map[int]interface{}, length)
-	//	for i := uint32(0); i < length; i++ {
-	//		var itrfc interface{}
-	//		bbr.Skip(s.decode(bbr.BytesFromCursor(), &itrfc))
-	//		tmtd[int(bytesx.Uint64(bbr.Read(8)))] = itrfc
-	//	}
-	//	field.Set(reflect.ValueOf(tmtd))
-	//	return
-	//case map[int64]interface{}:
-	//	tmtd := make(map[int64]interface{}, length)
-	//	for i := uint32(0); i < length; i++ {
-	//		var itrfc interface{}
-	//		bbr.Skip(s.decode(bbr.BytesFromCursor(), &itrfc))
-	//		tmtd[int64(bytesx.Uint64(bbr.Read(8)))] = itrfc
-	//	}
-	//	field.Set(reflect.ValueOf(tmtd))
-	//	return
-	//case map[string]interface{}:
-	//	tmtd := make(map[string]interface{}, length)
-	//	for i := uint32(0); i < length; i++ {
-	//		var itrfc interface{}
-	//		bbr.Skip(s.decode(bbr.BytesFromCursor(), &itrfc))
-	//		tmtd[s.decodeUnsafeString(bbr)] = itrfc
-	//	}
-	//	field.Set(reflect.ValueOf(tmtd))
-	//	return
-	//case map[interface{}]interface{}:
-	//	tmtd := make(map[interface{}]interface{}, length)
-	//	for i := uint32(0); i < length; i++ {
-	//		var itrfcKey interface{}
-	//		bbr.Skip(s.decode(bbr.BytesFromCursor(), &itrfcKey))
-	//		var itrfcType interface{}
-	//		bbr.Skip(s.decode(bbr.BytesFromCursor(), &itrfcType))
-	//		tmtd[itrfcKey] = itrfcType
-	//	}
-	//	field.Set(reflect.ValueOf(tmtd))
+	case map[int]interface{}:
+		// every entry takes at least its key and a type id
+		if !bbr.Ensure(length * s.minWireSize(12)) {
+			return
+		}
+
+		tmtd := make(map[int]interface{}, length)
+		for i := 0; i < length; i++ {
+			key := int(s.readInt(bbr))
+			tmtd[key] = s.interfaceDecode(bbr)
+			if bbr.Err() != nil {
+				bbr.AnnotateField(fmt.Sprintf("[%v]", key), "interface {}")
+				return
+			}
+		}
+		field.Set(reflect.ValueOf(tmtd))
+		return
+	case map[int64]interface{}:
+		// every entry takes at least its key and a type id
+		if !bbr.Ensure(length * s.minWireSize(12)) {
+			return
+		}
+
+		tmtd := make(map[int64]interface{}, length)
+		for i := 0; i < length; i++ {
+			key := s.readInt(bbr)
+			tmtd[key] = s.interfaceDecode(bbr)
+			if bbr.Err() != nil {
+				bbr.AnnotateField(fmt.Sprintf("[%v]", key), "interface {}")
+				return
+			}
+		}
+		field.Set(reflect.ValueOf(tmtd))
+		return
+	case map[string]interface{}:
+		// every entry takes at least its key length and a type id
+		if !bbr.Ensure(length * s.minWireSize(8)) {
+			return
+		}
+
+		tmtd := make(map[string]interface{}, length)
+		for i := 0; i < length; i++ {
+			key := s.decodeString(bbr)
+			tmtd[key] = s.interfaceDecode(bbr)
+			if bbr.Err() != nil {
+				bbr.AnnotateField(fmt.Sprintf("[%v]", key), "interface {}")
+				return
+			}
+		}
+		field.Set(reflect.ValueOf(tmtd))
+		return
+	case map[interface{}]interface{}:
+		// every entry takes at least two type ids
+		if !bbr.Ensure(length * s.minWireSize(8)) {
+			return
+		}
+
+		tmtd := make(map[interface{}]interface{}, length)
+		for i := 0; i < length; i++ {
+			key := s.interfaceDecode(bbr)
+			if bbr.Err() != nil {
+				bbr.AnnotateField("["+strconv.Itoa(i)+"]", "interface {}")
+				return
+			}
+
+			if key != nil && !reflect.TypeOf(key).Comparable() {
+				bbr.Fail(&models.UnsupportedTypeError{Type: reflect.TypeOf(key), Kind: reflect.TypeOf(key).Kind()})
+				bbr.AnnotateField("["+strconv.Itoa(i)+"]", "interface {}")
+				return
+			}
+
+			tmtd[key] = s.interfaceDecode(bbr)
+			if bbr.Err() != nil {
+				bbr.AnnotateField(fmt.Sprintf("[%v]", key), "interface {}")
+				return
+			}
+		}
+		field.Set(reflect.ValueOf(tmtd))
+		return
+	}
+}
+
+// ################################################################################################################## \\
+// envelope & trailer
+// ################################################################################################################## \\
+
+// open reads and verifies what surrounds the encoded value: the envelope header, the checksum trailer and the
+// schema fingerprint. It returns the serializer matching the envelope flags.
+func (s *RawBinarySerializer) open(bbr *bytesx.Reader, typ reflect.Type) (*RawBinarySerializer, error) {
+	d, err := s.readEnvelope(bbr)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.opts.Checksum {
+		if err = binaryx.ReadChecksum(bbr); err != nil {
+			return nil, err
+		}
+	}
+
+	if d.opts.SchemaFingerprint {
+		if err = binaryx.ReadFingerprint(bbr, typ, d.opts); err != nil {
+			return nil, err
+		}
+	}
+
+	return d, nil
+}
+
+// readEnvelope reads the envelope header from bbr when envelopes are enabled. It returns the serializer
+// matching the flags found in the header, which is s unless the payload was written in another mode.
+func (s *RawBinarySerializer) readEnvelope(bbr *bytesx.Reader) (*RawBinarySerializer, error) {
+	if !s.opts.Envelope {
+		return s, nil
+	}
+
+	flags, err := binaryx.ReadEnvelope(bbr, binaryx.FormatRawBinary)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := s.opts
+	opts.Varint = flags&binaryx.FlagVarint != 0
+	opts.FieldIDs = flags&binaryx.FlagFieldIDs != 0
+	// checks enabled on s are kept so that a corrupted header cannot turn them off
+	opts.Checksum = opts.Checksum || flags&binaryx.FlagChecksum != 0
+	opts.SchemaFingerprint = opts.SchemaFingerprint || flags&binaryx.FlagSchemaFingerprint != 0
+	if opts == s.opts {
+		return s, nil
+	}
+
+	d := *s
+	d.opts = opts
+	return &d, nil
+}
+
+// ################################################################################################################## \\
+// codec encoder
+// ################################################################################################################## \\
+
+// codecEncode hands value to the codec registered for its type.
+// It reports false when there is none.
+func (s *RawBinarySerializer) codecEncode(bbw *bytesx.Writer, value reflect.Value) bool {
+	codec, ok := binaryx.CodecOf(value.Type())
+	if !ok {
+		return false
+	}
+
+	if err := codec.Encode(bbw, value); err != nil {
+		bbw.Fail(&models.CodecError{Type: value.Type(), Err: err})
+	}
+
+	return true
+}
+
+// codecDecode hands value to the codec registered for its type.
+// It reports false when there is none.
+func (s *RawBinarySerializer) codecDecode(bbr *bytesx.Reader, value reflect.Value) bool {
+	codec, ok := binaryx.CodecOf(value.Type())
+	if !ok {
+		return false
+	}
+
+	// a truncated payload is reported as such, whatever the codec made of it
+	if err := codec.Decode(bbr, value); err != nil && bbr.Err() == nil {
+		bbr.Fail(&models.CodecError{Type: value.Type(), Err: err})
+	}
+
+	return true
+}
+
+// ################################################################################################################## \\
+// marshaler encoder
+// ################################################################################################################## \\
+
+// marshalerEncode writes the output of the encoding.BinaryMarshaler or encoding.TextMarshaler implemented by value
+// as length-prefixed bytes. It reports false when value implements neither.
+func (s *RawBinarySerializer) marshalerEncode(bbw *bytesx.Writer, value reflect.Value) bool {
+	if binaryx.MarshalerOf(value.Type()) == binaryx.NoMarshaler {
+		return false
+	}
+
+	bs, err := binaryx.Marshal(value)
+	if err != nil {
+		bbw.Fail(err)
+		return true
+	}
+
+	s.putLength(bbw, len(bs))
+	bbw.Write(bs)
+	return true
+}
+
+// marshalerDecode feeds the bytes written by marshalerEncode to the matching unmarshaler of value.
+// It reports false when value implements neither.
+func (s *RawBinarySerializer) marshalerDecode(bbr *bytesx.Reader, value reflect.Value) bool {
+	if binaryx.MarshalerOf(value.Type()) == binaryx.NoMarshaler {
+		return false
+	}
+
+	bs := bbr.Read(s.readLength(bbr))
+	if bbr.Err() != nil {
+		return true
+	}
+
+	if err := binaryx.Unmarshal(value, bs); err != nil {
+		bbr.Fail(err)
+	}
+
+	return true
+}
+
+// ################################################################################################################## \\
+// interface encoder
+// ################################################################################################################## \\
+
+// interfaceEncode writes the registered type id of data followed by data itself.
+// A nil interface is written as binaryx.NilTypeID alone.
+func (s *RawBinarySerializer) interfaceEncode(bbw *bytesx.Writer, data interface{}) {
+	if data == nil {
+		bbw.Write(bytesx.AddUint32(binaryx.NilTypeID))
+		return
+	}
+
+	value := reflect.ValueOf(data)
+	id, ok := binaryx.TypeID(value.Type())
+	if !ok {
+		bbw.Fail(&models.UnregisteredTypeError{Type: value.Type()})
+		return
+	}
+
+	bbw.Write(bytesx.AddUint32(id))
+	s.reflectEncode(bbw, value)
+}
+
+// interfaceDecode reads a value written by interfaceEncode into a new interface{}.
+func (s *RawBinarySerializer) interfaceDecode(bbr *bytesx.Reader) interface{} {
+	if value := s.interfaceDecodeValue(bbr, binaryx.AnyType); value.IsValid() {
+		return value.Interface()
+	}
+
+	return nil
+}
+
+// interfaceDecodeInto reads a value written by interfaceEncode into the interface held by field.
+func (s *RawBinarySerializer) interfaceDecodeInto(bbr *bytesx.Reader, field reflect.Value) {
+	if value := s.interfaceDecodeValue(bbr, field.Type()); value.IsValid() {
+		field.Set(value)
+		return
+	}
+
+	field.Set(reflect.Zero(field.Type()))
+}
+
+// interfaceDecodeValue returns the concrete value read from the payload, or the zero reflect.Value for nil
+// interfaces and errors.
+func (s *RawBinarySerializer) interfaceDecodeValue(bbr *bytesx.Reader, typ reflect.Type) reflect.Value {
+	id := bbr.Uint32()
+	if id == binaryx.NilTypeID {
+		return reflect.Value{}
+	}
+
+	concrete, ok := binaryx.TypeByID(id)
+	if !ok {
+		bbr.Fail(&models.UnregisteredTypeError{ID: id})
+		return reflect.Value{}
+	}
+
+	if !concrete.AssignableTo(typ) {
+		bbr.Fail(&models.InterfaceTypeError{Type: concrete, Interface: typ})
+		return reflect.Value{}
+	}
+
+	value := reflect.New(concrete).Elem()
+	s.reflectDecode(bbr, value)
+	if bbr.Err() != nil {
+		return reflect.Value{}
+	}
+
+	return value
+}
+
+// ################################################################################################################## \\
+// varint encoder
+// ################################################################################################################## \\
+
+// putLength writes the length of a string, slice, map or marshaled value.
+func (s *RawBinarySerializer) putLength(bbw *bytesx.Writer, n int) {
+	if s.opts.Varint {
+		bbw.PutUvarint(uint64(n))
+		return
+	}
+
+	bbw.Write(bytesx.AddUint32(uint32(n)))
+}
+
+// readLength reads a length written by putLength.
+func (s *RawBinarySerializer) readLength(bbr *bytesx.Reader) int {
+	if s.opts.Varint {
+		return bbr.UvarintLength()
+	}
+
+	return int(bbr.Uint32())
+}
+
+// putInt writes the int and int64 keys and values of the specialised map encoders.
+func (s *RawBinarySerializer) putInt(bbw *bytesx.Writer, v int64) {
+	if s.opts.Varint {
+		bbw.PutVarint(v)
+		return
+	}
+
+	bbw.Write(bytesx.AddUint64(uint64(v)))
+}
+
+// readInt reads an integer written by putInt.
+func (s *RawBinarySerializer) readInt(bbr *bytesx.Reader) int64 {
+	if s.opts.Varint {
+		return bbr.Varint()
+	}
+
+	return int64(bbr.Uint64())
+}
+
+// minWireSize returns the least number of bytes taken by a length or an integer whose fixed-width layout
+// takes size bytes.
+func (s *RawBinarySerializer) minWireSize(size int) int {
+	if s.opts.Varint {
+		return 1
+	}
+
+	return size
+}
+
+// serializeReflectVarint writes the multi-byte integer kinds as varints.
+// It reports false for any other kind.
+func (s *RawBinarySerializer) serializeReflectVarint(bbw *bytesx.Writer, v *reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int16, reflect.Int32, reflect.Int64:
+		bbw.PutVarint(v.Int())
+		return true
+	case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		bbw.PutUvarint(v.Uint())
+		return true
 	default:
-		field.Set(reflect.MakeMapWithSize(field.Type(), length))
+		return false
+	}
+}
+
+// deserializeVarint reads the varints written by serializeReflectVarint, rejecting the ones overflowing field.
+// It reports false for any other kind.
+func (s *RawBinarySerializer) deserializeVarint(bbr *bytesx.Reader, field *reflect.Value) bool {
+	switch field.Kind() {
+	case reflect.Int, reflect.Int16, reflect.Int32, reflect.Int64:
+		offset := bbr.Yield()
+		v := bbr.Varint()
+		if field.OverflowInt(v) {
+			bbr.Fail(&models.DecodeError{Offset: offset, Reason: "varint overflows " + field.Kind().String()})
+			return true
+		}
+
+		field.SetInt(v)
+		return true
+	case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		offset := bbr.Yield()
+		v := bbr.Uvarint()
+		if field.OverflowUint(v) {
+			bbr.Fail(&models.DecodeError{Offset: offset, Reason: "varint overflows " + field.Kind().String()})
+			return true
+		}
+
+		field.SetUint(v)
+		return true
+	default:
+		return false
+	}
+}
+
+// serializeVarintSliceArray writes the elements of integer slices as varints.
+// It reports false for any other slice type.
+func (s *RawBinarySerializer) serializeVarintSliceArray(bbw *bytesx.Writer, field *reflect.Value, length int) bool {
+	switch binaryx.PrimitiveSlice(field.Type()) {
+	case "[]int", "[]int16", "[]int32", "[]int64":
+		for i := 0; i < length; i++ {
+			bbw.PutVarint(field.Index(i).Int())
+		}
+
+		return true
+	case "[]uint", "[]uint16", "[]uint32", "[]uint64":
 		for i := 0; i < length; i++ {
-			keyValue := reflect.New(field.Type().Key()).Elem()
-			bbr.Skip(s.reflectDecode(bbr.BytesFromCursor(), keyValue))
+			bbw.PutUvarint(field.Index(i).Uint())
+		}
+
+		return true
+	default:
+		return false
+	}
+}
 
-			valueValue := reflect.New(field.Type().Elem()).Elem()
-			bbr.Skip(s.reflectDecode(bbr.BytesFromCursor(), valueValue))
+// deserializeVarintSliceArray reads the slices written by serializeVarintSliceArray.
+// It reports false for any other slice type.
+func (s *RawBinarySerializer) deserializeVarintSliceArray(bbr *bytesx.Reader, field *reflect.Value, length int) bool {
+	switch binaryx.PrimitiveSlice(field.Type()) {
+	case "[]int", "[]int16", "[]int32", "[]int64", "[]uint", "[]uint16", "[]uint32", "[]uint64":
+		// every varint takes at least one byte
+		if !bbr.Ensure(length) {
+			return true
+		}
 
-			field.SetMapIndex(keyValue, valueValue)
+		slice := reflect.MakeSlice(field.Type(), length, length)
+		for i := 0; i < length; i++ {
+			f := slice.Index(i)
+			s.deserializeVarint(bbr, &f)
+			if bbr.Err() != nil {
+				bbr.AnnotateField("["+strconv.Itoa(i)+"]", f.Type().String())
+				return true
+			}
 		}
+
+		field.Set(slice)
+		return true
+	default:
+		return false
 	}
 }
 
@@ -1032,11 +1996,25 @@ func (s *RawBinarySerializer) mapDecode(bbr *bytesx.Reader, field *reflect.Value
 
 func (s *RawBinarySerializer) encodeUnsafeString(bbw *bytesx.Writer, str string) {
 	strLen := len(str)
-	bbw.Write(bytesx.AddUint32(uint32(strLen)))
+	s.putLength(bbw, strLen)
 	bbw.Write(unsafe.Slice(unsafe.StringData(str), strLen))
 }
 
-func (s *RawBinarySerializer) decodeUnsafeString(bbr *bytesx.Reader) string {
-	bs := bbr.Read(int(bytesx.Uint32(bbr.Read(4))))
-	return unsafe.String(unsafe.SliceData(bs), len(bs))
+func (s *RawBinarySerializer) decodeString(bbr *bytesx.Reader) string {
+	bs := bbr.Read(s.readLength(bbr))
+	if s.opts.ZeroCopy {
+		return unsafe.String(unsafe.SliceData(bs), len(bs))
+	}
+
+	return string(bs)
+}
+
+// readBytes returns the next n bytes of bbr, aliasing the payload only in the zero-copy mode.
+func (s *RawBinarySerializer) readBytes(bbr *bytesx.Reader, n int) []byte {
+	bs := bbr.Read(n)
+	if s.opts.ZeroCopy {
+		return bs
+	}
+
+	return bytes.Clone(bs)
 }
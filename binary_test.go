@@ -3,7 +3,14 @@
 package serializer
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"math"
+	"net/netip"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -11,6 +18,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/testmodels"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
 )
 
 func TestBinarySerializer(t *testing.T) {
@@ -1802,4 +1810,1961 @@ func TestBinarySerializer(t *testing.T) {
 			})
 		})
 	})
+
+	t.Run("malformed payload", func(t *testing.T) {
+		t.Run("every truncation fails without panicking", func(t *testing.T) {
+			msg := &testmodels.SliceTestData{
+				IntList:    []int{1, 2, 3},
+				IntIntList: [][]int{{1, 2}, {3}},
+				StrList:    []string{"first-item", "second-item"},
+				StructList: []testmodels.SliceItem{
+					{Int: 100, Str: "any string", Bool: true},
+				},
+				PtrStructList: []*testmodels.SliceItem{
+					{Int: 500, Str: "any other string"},
+					nil,
+				},
+			}
+
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(msg)
+			require.NoError(t, err)
+
+			for size := 0; size < len(bs); size++ {
+				var target testmodels.SliceTestData
+				assert.NotPanics(t, func() {
+					err = s.Deserialize(bs[:size], &target)
+				})
+
+				var decodeErr *models.DecodeError
+				require.ErrorAs(t, err, &decodeErr, "size: %d", size)
+				assert.Equal(t, size, decodeErr.Offset+decodeErr.Remaining)
+			}
+		})
+
+		t.Run("truncated nested field", func(t *testing.T) {
+			msg := &testmodels.Item{
+				Id:     "any-item",
+				ItemId: 100,
+				Number: 5_000_000_000,
+				SubItem: &testmodels.SubItem{
+					Date:     time.Now().Unix(),
+					Amount:   1_000_000_000,
+					ItemCode: "code-status",
+				},
+			}
+
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(msg)
+			require.NoError(t, err)
+
+			var target testmodels.Item
+			err = s.Deserialize(bs[:len(bs)-3], &target)
+
+			var decodeErr *models.DecodeError
+			require.ErrorAs(t, err, &decodeErr)
+			assert.Equal(t, "SubItem.ItemCode", decodeErr.Field)
+			assert.Equal(t, "string", decodeErr.Type)
+			assert.Equal(t, len("code-status"), decodeErr.Expected)
+			assert.Equal(t, len(bs)-len("code-status"), decodeErr.Offset)
+			assert.Equal(t, len("code-status")-3, decodeErr.Remaining)
+		})
+
+		t.Run("corrupted length prefix", func(t *testing.T) {
+			msg := &testmodels.StructSliceTestData{
+				StructList: []testmodels.StructTestData{
+					{Bool: true, String: "any-string", Int64: 10},
+				},
+			}
+
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(msg)
+			require.NoError(t, err)
+
+			// claims four billion items for a payload that holds only one
+			copy(bs, []byte{0xff, 0xff, 0xff, 0xf0})
+
+			var target testmodels.StructSliceTestData
+			err = s.Deserialize(bs, &target)
+
+			var decodeErr *models.DecodeError
+			require.ErrorAs(t, err, &decodeErr)
+			assert.Equal(t, "StructList", decodeErr.Field)
+			assert.Equal(t, 4, decodeErr.Offset)
+		})
+
+		t.Run("corrupted primitive slice length", func(t *testing.T) {
+			msg := &testmodels.Int64SliceTestData{
+				Int64List: []int64{1, 2, 3},
+			}
+
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(msg)
+			require.NoError(t, err)
+
+			copy(bs, []byte{0xff, 0xff, 0xff, 0x0f})
+
+			var target testmodels.Int64SliceTestData
+			err = s.Deserialize(bs, &target)
+
+			var decodeErr *models.DecodeError
+			require.ErrorAs(t, err, &decodeErr)
+			assert.Equal(t, "Int64List", decodeErr.Field)
+			assert.Equal(t, 0x0fffffff*8, decodeErr.Expected)
+			assert.Equal(t, 24, decodeErr.Remaining)
+		})
+
+		t.Run("corrupted zero-size length", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			// claims two billion elements that take no byte in the payload
+			bs := []byte{0xff, 0xff, 0xff, 0x7f}
+
+			var slice []struct{}
+			err := s.Deserialize(bs, &slice)
+
+			var decodeErr *models.DecodeError
+			require.ErrorAs(t, err, &decodeErr)
+			assert.Contains(t, decodeErr.Reason, "zero-size elements")
+
+			var m map[struct{}]struct{}
+			err = s.Deserialize(bs, &m)
+			require.ErrorAs(t, err, &decodeErr)
+			assert.Contains(t, decodeErr.Reason, "zero-size elements")
+
+			bs, err = s.Serialize(make([]struct{}, 1000))
+			require.NoError(t, err)
+
+			err = s.Deserialize(bs, &slice)
+			require.NoError(t, err)
+			assert.Len(t, slice, 1000)
+		})
+
+		t.Run("invalid targets", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(testmodels.Item{Id: "item"})
+			require.NoError(t, err)
+
+			var nilItem *testmodels.Item
+			for name, target := range map[string]interface{}{
+				"struct":               testmodels.Item{},
+				"int64":                int64(0),
+				"nil":                  nil,
+				"typed nil":            nilItem,
+				"pointer to nil":       &nilItem,
+				"pointer to nil slice": (*[]string)(nil),
+			} {
+				t.Run(name, func(t *testing.T) {
+					var targetErr *models.InvalidTargetError
+					assert.NotPanics(t, func() {
+						err = s.Deserialize(bs, target)
+					})
+					assert.ErrorAs(t, err, &targetErr)
+
+					assert.NotPanics(t, func() {
+						err = s.DataRebind(testmodels.Item{Id: "item"}, target)
+					})
+					assert.ErrorAs(t, err, &targetErr)
+				})
+			}
+		})
+
+		t.Run("Unmarshal surfaces the error", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			var target testmodels.Item
+			err := s.Unmarshal([]byte{1, 0}, &target)
+			var decodeErr *models.DecodeError
+			assert.ErrorAs(t, err, &decodeErr)
+
+			var str string
+			err = s.Deserialize([]byte{}, &str)
+			assert.ErrorAs(t, err, &decodeErr)
+			assert.Equal(t, "", decodeErr.Field)
+		})
+	})
+
+	t.Run("unsupported kinds", func(t *testing.T) {
+		t.Run("field", func(t *testing.T) {
+			msg := &testmodels.UnsupportedFieldsTestData{
+				Name:  "any-name",
+				Done:  make(chan struct{}),
+				Count: 10,
+			}
+
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(msg)
+			assert.Nil(t, bs)
+
+			var unsupportedErr *models.UnsupportedTypeError
+			require.ErrorAs(t, err, &unsupportedErr)
+			assert.Equal(t, "Done", unsupportedErr.Field)
+			assert.Equal(t, reflect.Chan, unsupportedErr.Kind)
+
+			var target testmodels.UnsupportedFieldsTestData
+			err = s.DataRebind(msg, &target)
+			require.ErrorAs(t, err, &unsupportedErr)
+		})
+
+		t.Run("nested field", func(t *testing.T) {
+			msg := &testmodels.NestedUnsupportedFieldsTestData{
+				Id: "any-id",
+				Items: []testmodels.UnsupportedFieldsTestData{
+					{Name: "any-name"},
+				},
+			}
+
+			s := NewBinarySerializer()
+
+			_, err := s.Serialize(msg)
+
+			var unsupportedErr *models.UnsupportedTypeError
+			require.ErrorAs(t, err, &unsupportedErr)
+			assert.Equal(t, "Items[0].Done", unsupportedErr.Field)
+		})
+
+		t.Run("top level value", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			_, err := s.Serialize([]func(){})
+
+			var unsupportedErr *models.UnsupportedTypeError
+			require.ErrorAs(t, err, &unsupportedErr)
+			assert.Equal(t, "", unsupportedErr.Field)
+			assert.Equal(t, reflect.Func, unsupportedErr.Kind)
+			assert.Equal(t, reflect.TypeOf([]func(){}), unsupportedErr.Type)
+		})
+
+		t.Run("skip unsupported fields", func(t *testing.T) {
+			msg := &testmodels.NestedUnsupportedFieldsTestData{
+				Id: "any-id",
+				Items: []testmodels.UnsupportedFieldsTestData{
+					{
+						Name:     "any-name",
+						Done:     make(chan struct{}),
+						Callback: func() error { return nil },
+						Handle:   1 << 10,
+						Count:    10,
+					},
+					{
+						Name:  "any-other-name",
+						Count: 20,
+					},
+				},
+			}
+
+			s := NewBinarySerializer(WithSkipUnsupportedFields())
+
+			bs, err := s.Serialize(msg)
+			require.NoError(t, err)
+
+			var target testmodels.NestedUnsupportedFieldsTestData
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, &testmodels.NestedUnsupportedFieldsTestData{
+				Id: "any-id",
+				Items: []testmodels.UnsupportedFieldsTestData{
+					{Name: "any-name", Count: 10},
+					{Name: "any-other-name", Count: 20},
+				},
+			}, &target)
+		})
+	})
+
+	t.Run("struct tags", func(t *testing.T) {
+		t.Run("skip and order", func(t *testing.T) {
+			msg := &testmodels.TaggedStructTestData{
+				Name:  "any-name",
+				Cache: map[string]string{"any-key": "any-value"},
+				Id:    math.MaxInt64,
+				Note:  "any-note",
+				Count: math.MaxUint32,
+				Done:  make(chan struct{}),
+			}
+
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(msg)
+			require.NoError(t, err)
+
+			equivalentBs, err := s.Serialize(&testmodels.TaggedStructWireEquivalentTestData{
+				Id:    msg.Id,
+				Count: msg.Count,
+				Name:  msg.Name,
+				Note:  msg.Note,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, equivalentBs, bs)
+
+			var target testmodels.TaggedStructTestData
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, testmodels.TaggedStructTestData{
+				Name:  msg.Name,
+				Id:    msg.Id,
+				Note:  msg.Note,
+				Count: msg.Count,
+			}, target)
+		})
+
+		t.Run("renamed field in error path", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			var target testmodels.TaggedStructTestData
+			err := s.Deserialize([]byte{1, 2, 3}, &target)
+
+			var decodeErr *models.DecodeError
+			require.ErrorAs(t, err, &decodeErr)
+			assert.Equal(t, "identifier", decodeErr.Field)
+		})
+
+		t.Run("invalid tag", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			_, err := s.Serialize(&testmodels.InvalidTagTestData{Name: "any-name"})
+
+			var tagErr *models.StructTagError
+			require.ErrorAs(t, err, &tagErr)
+			assert.Equal(t, "Name", tagErr.Field)
+
+			var target testmodels.InvalidTagTestData
+			err = s.Deserialize([]byte{0, 0, 0, 0}, &target)
+			require.ErrorAs(t, err, &tagErr)
+		})
+	})
+
+	t.Run("unexported fields", func(t *testing.T) {
+		createdAt := time.Unix(1_700_000_000, 123_456_789).UTC()
+		msg := testmodels.NewUnexportedFieldsTestData(
+			"any-name", createdAt, "any-secret", math.MaxInt64, []string{"first-tag", "second-tag"},
+			&testmodels.SubItem{Date: 10, Amount: 20, ItemCode: "any-code"},
+		)
+
+		t.Run("skipped by default", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(msg)
+			require.NoError(t, err)
+
+			var target testmodels.UnexportedFieldsTestData
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, testmodels.UnexportedFieldsTestData{Name: "any-name", CreatedAt: createdAt}, target)
+		})
+
+		t.Run("included", func(t *testing.T) {
+			s := NewBinarySerializer(WithUnexportedFields(IncludeUnexportedFields))
+
+			bs, err := s.Serialize(msg)
+			require.NoError(t, err)
+
+			var target testmodels.UnexportedFieldsTestData
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, *msg, target)
+			assert.True(t, createdAt.Equal(target.CreatedAt))
+		})
+
+		t.Run("included from a non addressable value", func(t *testing.T) {
+			s := NewBinarySerializer(WithUnexportedFields(IncludeUnexportedFields))
+
+			bs, err := s.Serialize(map[string]testmodels.UnexportedFieldsTestData{"any-key": *msg})
+			require.NoError(t, err)
+
+			var target map[string]testmodels.UnexportedFieldsTestData
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, *msg, target["any-key"])
+		})
+	})
+
+	t.Run("interface values", func(t *testing.T) {
+		Register("testmodels.Money", testmodels.Money{})
+		Register("*testmodels.SubItem", &testmodels.SubItem{})
+
+		t.Run("fields, slices and maps", func(t *testing.T) {
+			msg := &testmodels.InterfaceTestData{
+				Id:      "any-id",
+				Payload: testmodels.Money{Amount: math.MaxInt64, Currency: "EUR"},
+				Label:   testmodels.Money{Amount: 10, Currency: "BRL"},
+				Items: []interface{}{
+					1, int8(-2), uint16(3), float32(4.5), "five", true, nil, []byte("six"),
+					testmodels.Money{Amount: 7, Currency: "USD"},
+					[]interface{}{"nested", int64(8)},
+				},
+				Metadata: map[string]interface{}{
+					"count":  int64(3),
+					"tags":   []string{"first-tag", "second-tag"},
+					"nested": map[string]interface{}{"ok": true, "none": nil},
+				},
+				Indexed: map[int]interface{}{
+					1: "one",
+					2: &testmodels.SubItem{Date: 10, Amount: 20, ItemCode: "any-code"},
+				},
+				Dynamic: map[interface{}]interface{}{
+					"key":    int32(1),
+					int64(2): "two",
+				},
+			}
+
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(msg)
+			require.NoError(t, err)
+
+			var target testmodels.InterfaceTestData
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, *msg, target)
+			assert.Equal(t, "10 BRL", target.Label.String())
+		})
+
+		t.Run("top level values", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			items := []interface{}{"any-item", int64(1), testmodels.Money{Amount: 2, Currency: "EUR"}}
+			bs, err := s.Serialize(items)
+			require.NoError(t, err)
+
+			var itemsTarget []interface{}
+			err = s.Deserialize(bs, &itemsTarget)
+			require.NoError(t, err)
+			assert.Equal(t, items, itemsTarget)
+
+			metadata := map[string]interface{}{"any-key": "any-value", "count": uint64(1)}
+			bs, err = s.Serialize(metadata)
+			require.NoError(t, err)
+
+			var metadataTarget map[string]interface{}
+			err = s.Deserialize(bs, &metadataTarget)
+			require.NoError(t, err)
+			assert.Equal(t, metadata, metadataTarget)
+
+			var value interface{} = testmodels.Money{Amount: 3, Currency: "BRL"}
+			bs, err = s.Serialize(&value)
+			require.NoError(t, err)
+
+			var valueTarget interface{}
+			err = s.Deserialize(bs, &valueTarget)
+			require.NoError(t, err)
+			assert.Equal(t, value, valueTarget)
+		})
+
+		t.Run("nil values reset the target", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(&testmodels.InterfaceTestData{Id: "any-id"})
+			require.NoError(t, err)
+
+			target := testmodels.InterfaceTestData{
+				Payload: "stale",
+				Label:   testmodels.Money{Amount: 1, Currency: "EUR"},
+			}
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, testmodels.InterfaceTestData{Id: "any-id"}, target)
+		})
+
+		t.Run("unregistered type", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			_, err := s.Serialize(&testmodels.InterfaceTestData{
+				Metadata: map[string]interface{}{"any-key": testmodels.StructTestData{}},
+			})
+
+			var unregisteredErr *models.UnregisteredTypeError
+			require.ErrorAs(t, err, &unregisteredErr)
+			assert.Equal(t, "Metadata[any-key]", unregisteredErr.Field)
+			assert.Equal(t, reflect.TypeOf(testmodels.StructTestData{}), unregisteredErr.Type)
+		})
+
+		t.Run("unknown type id", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(&testmodels.InterfaceTestData{Payload: int64(1)})
+			require.NoError(t, err)
+
+			// Id is an empty string, so the type id of Payload starts right after its length
+			copy(bs[4:8], []byte{0xff, 0xff, 0xff, 0xff})
+
+			var target testmodels.InterfaceTestData
+			err = s.Deserialize(bs, &target)
+
+			var unregisteredErr *models.UnregisteredTypeError
+			require.ErrorAs(t, err, &unregisteredErr)
+			assert.Equal(t, "Payload", unregisteredErr.Field)
+			assert.Equal(t, uint32(0xffffffff), unregisteredErr.ID)
+		})
+
+		t.Run("type not assignable to the interface", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			var value interface{} = int64(1)
+			bs, err := s.Serialize(&value)
+			require.NoError(t, err)
+
+			var target fmt.Stringer
+			err = s.Deserialize(bs, &target)
+
+			var interfaceErr *models.InterfaceTypeError
+			require.ErrorAs(t, err, &interfaceErr)
+			assert.Equal(t, reflect.TypeOf(int64(0)), interfaceErr.Type)
+		})
+
+		t.Run("conflicting registrations panic", func(t *testing.T) {
+			assert.Panics(t, func() { Register("testmodels.Money", testmodels.SubItem{}) })
+			assert.Panics(t, func() { Register("any-other-name", testmodels.Money{}) })
+			assert.NotPanics(t, func() { Register("testmodels.Money", testmodels.Money{}) })
+		})
+	})
+
+	t.Run("marshaler hooks", func(t *testing.T) {
+		t.Run("fields, slices, maps and pointers", func(t *testing.T) {
+			discount := testmodels.NewCents(-50)
+			msg := &testmodels.MarshalerTestData{
+				Price:     testmodels.NewCents(math.MaxInt64),
+				Code:      testmodels.Code(42),
+				Addr:      netip.MustParseAddr("2001:db8::1"),
+				CreatedAt: time.Unix(1_700_000_000, 123_456_789).UTC(),
+				Prices:    []testmodels.Cents{testmodels.NewCents(1), testmodels.NewCents(2)},
+				Codes: map[testmodels.Code]testmodels.Cents{
+					testmodels.Code(1): testmodels.NewCents(100),
+					testmodels.Code(2): testmodels.NewCents(200),
+				},
+				Discount: &discount,
+			}
+
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(msg)
+			require.NoError(t, err)
+
+			var target testmodels.MarshalerTestData
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, *msg, target)
+		})
+
+		t.Run("output is embedded as length-prefixed bytes", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(testmodels.Code(7))
+			require.NoError(t, err)
+			assert.Equal(t, []byte{3, 0, 0, 0, 'C', '-', '7'}, bs)
+
+			var target testmodels.Code
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, testmodels.Code(7), target)
+		})
+
+		t.Run("marshaler error", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			_, err := s.Serialize(&testmodels.BrokenMarshalerTestData{Name: "any-name"})
+
+			var marshalerErr *models.MarshalerError
+			require.ErrorAs(t, err, &marshalerErr)
+			assert.Equal(t, "Broken", marshalerErr.Field)
+			assert.Equal(t, "MarshalBinary", marshalerErr.Method)
+			assert.ErrorIs(t, err, testmodels.ErrBrokenMarshaler)
+		})
+
+		t.Run("unmarshaler error", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(map[string]string{"any-key": "not-a-code"})
+			require.NoError(t, err)
+
+			var target map[string]testmodels.Code
+			err = s.Deserialize(bs, &target)
+
+			var marshalerErr *models.MarshalerError
+			require.ErrorAs(t, err, &marshalerErr)
+			assert.Equal(t, "[any-key]", marshalerErr.Field)
+			assert.Equal(t, "UnmarshalText", marshalerErr.Method)
+		})
+	})
+
+	t.Run("registered codecs", func(t *testing.T) {
+		t.Run("fields, slices, maps and pointers", func(t *testing.T) {
+			msg := &testmodels.CodecTestData{
+				Name:   "any-name",
+				Origin: testmodels.Coordinates{Lat: -23.5505, Lng: -46.6333},
+				Stops: []testmodels.Coordinates{
+					{Lat: 52.3676, Lng: 4.9041},
+					{Lat: 38.7223, Lng: -9.1393},
+				},
+				ByName: map[string]testmodels.Coordinates{
+					"any-stop": {Lat: 1, Lng: 2},
+				},
+				Last: &testmodels.Coordinates{Lat: 90, Lng: 180},
+			}
+
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(msg)
+			require.NoError(t, err)
+
+			var target testmodels.CodecTestData
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, *msg, target)
+		})
+
+		t.Run("codec output is the wire format", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(testmodels.Coordinates{Lat: 1, Lng: 2, Updates: make(chan struct{})})
+			require.NoError(t, err)
+			assert.Len(t, bs, 16)
+
+			var target testmodels.Coordinates
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, testmodels.Coordinates{Lat: 1, Lng: 2}, target)
+		})
+
+		t.Run("encoder error", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			_, err := s.Serialize(&testmodels.CodecTestData{
+				Stops: []testmodels.Coordinates{{Lat: 1}, {Lat: 91}},
+			})
+
+			var codecErr *models.CodecError
+			require.ErrorAs(t, err, &codecErr)
+			assert.Equal(t, "Stops[1]", codecErr.Field)
+			assert.ErrorIs(t, err, testmodels.ErrInvalidCoordinates)
+		})
+
+		t.Run("decoder error", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize([]float64{91, 0})
+			require.NoError(t, err)
+
+			var target testmodels.Coordinates
+			err = s.Deserialize(bs[4:], &target)
+
+			var codecErr *models.CodecError
+			require.ErrorAs(t, err, &codecErr)
+			assert.ErrorIs(t, err, testmodels.ErrInvalidCoordinates)
+		})
+
+		t.Run("truncated payload", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(&testmodels.CodecTestData{Name: "any-name"})
+			require.NoError(t, err)
+
+			var target testmodels.CodecTestData
+			// keep the name and half of the origin
+			err = s.Deserialize(bs[:4+len("any-name")+8], &target)
+
+			var decodeErr *models.DecodeError
+			require.ErrorAs(t, err, &decodeErr)
+			assert.Equal(t, "Origin", decodeErr.Field)
+		})
+	})
+
+	t.Run("time", func(t *testing.T) {
+		createdAt := time.Unix(1_700_000_000, 123_456_789).UTC()
+		deletedAt := time.Unix(1_800_000_000, 1).In(time.FixedZone("BRT", -3*60*60))
+
+		t.Run("fields, slices, maps and pointers", func(t *testing.T) {
+			msg := &testmodels.TimeTestData{
+				CreatedAt: createdAt,
+				DeletedAt: &deletedAt,
+				Timeout:   math.MaxInt64,
+				Events:    []time.Time{createdAt, deletedAt, {}},
+				Retries:   []time.Duration{time.Millisecond, -time.Hour},
+				ByTime: map[time.Time]string{
+					createdAt:                  "created",
+					createdAt.Add(time.Minute): "updated",
+					time.Unix(0, 0).UTC():      "epoch",
+					time.Time{}:                "zero",
+				},
+				Deadlines: map[string]time.Time{"any-deadline": deletedAt},
+				Windows:   map[time.Duration]time.Duration{time.Second: time.Minute},
+			}
+
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(msg)
+			require.NoError(t, err)
+
+			var target testmodels.TimeTestData
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, *msg, target)
+			assert.Equal(t, "BRT", target.DeletedAt.Location().String())
+		})
+
+		t.Run("compact layout", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(createdAt)
+			require.NoError(t, err)
+			assert.Len(t, bs, 8+4+4+1)
+
+			bs, err = s.Serialize(time.Duration(-1))
+			require.NoError(t, err)
+			assert.Equal(t, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, bs)
+
+			var target time.Duration
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, time.Duration(-1), target)
+		})
+
+		t.Run("varint durations", func(t *testing.T) {
+			s := NewBinarySerializer(WithVarint())
+
+			bs, err := s.Serialize(time.Second)
+			require.NoError(t, err)
+			assert.Equal(t, []byte{0x80, 0xa8, 0xd6, 0xb9, 0x07}, bs)
+
+			var target time.Duration
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, time.Second, target)
+
+			msg := &testmodels.TimeTestData{
+				Timeout: -time.Minute,
+				Retries: []time.Duration{time.Millisecond, -time.Hour},
+				Windows: map[time.Duration]time.Duration{time.Second: time.Minute},
+			}
+
+			bs, err = s.Serialize(msg)
+			require.NoError(t, err)
+
+			var data testmodels.TimeTestData
+			err = s.Deserialize(bs, &data)
+			require.NoError(t, err)
+			assert.Equal(t, *msg, data)
+		})
+
+		t.Run("monotonic reading is dropped", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			now := time.Now()
+			bs, err := s.Serialize(&now)
+			require.NoError(t, err)
+
+			var target time.Time
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.True(t, now.Equal(target))
+			assert.Equal(t, now.Round(0), target)
+		})
+
+		t.Run("named location", func(t *testing.T) {
+			loc, err := time.LoadLocation("America/Sao_Paulo")
+			if err != nil {
+				t.Skip("time zone database not available")
+			}
+
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(createdAt.In(loc))
+			require.NoError(t, err)
+
+			var target time.Time
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, loc, target.Location())
+			assert.True(t, createdAt.Equal(target))
+		})
+
+		t.Run("unnamed fixed zone", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			for _, value := range []string{"2024-01-02T03:04:05+01:00", "2024-01-02T03:04:05-00:30"} {
+				parsed, err := time.Parse(time.RFC3339, value)
+				require.NoError(t, err)
+
+				bs, err := s.Serialize(parsed)
+				require.NoError(t, err)
+
+				var target time.Time
+				err = s.Deserialize(bs, &target)
+				require.NoError(t, err)
+				assert.Equal(t, value, target.Format(time.RFC3339))
+				assert.Equal(t, parsed, target)
+			}
+		})
+
+		t.Run("truncated time", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(&testmodels.TimeTestData{CreatedAt: createdAt})
+			require.NoError(t, err)
+
+			var target testmodels.TimeTestData
+			err = s.Deserialize(bs[:10], &target)
+
+			var decodeErr *models.DecodeError
+			require.ErrorAs(t, err, &decodeErr)
+			assert.Equal(t, "CreatedAt", decodeErr.Field)
+		})
+	})
+
+	t.Run("varint", func(t *testing.T) {
+		str, i, b, bs := "any-string", math.MinInt64, true, []byte("any-bytes")
+		msg := &testmodels.TestData{
+			FieldStr:      "any-string",
+			FieldInt:      -8,
+			FieldBool:     true,
+			FieldBytes:    []byte("any-bytes"),
+			FieldStrPtr:   &str,
+			FieldIntPtr:   &i,
+			FieldBoolPtr:  &b,
+			FieldBytesPtr: &bs,
+			SubTestData: testmodels.SubTestData{
+				FieldStr:   "any-sub-string",
+				FieldInt32: math.MinInt32,
+				FieldInt64: math.MaxInt64,
+				FieldInt:   300,
+			},
+			SliceTestData: testmodels.SliceTestData{
+				IntList:       []int{0, -1, 1, math.MaxInt64},
+				IntIntList:    [][]int{{1, 2}, {-3}},
+				ThreeDIntList: [][][]int{{{1}, {2, 3}}},
+				StrList:       []string{"first-item", ""},
+				StructList:    []testmodels.SliceItem{{Int: -100, Str: "any string", Bool: true}},
+				PtrStructList: []*testmodels.SliceItem{{Int: 500}, nil},
+			},
+			MapTestData: testmodels.MapTestData{
+				Int64KeyMapInt64Value: map[int64]int64{-1: 1, math.MinInt64: math.MaxInt64},
+				StrKeyMapStrValue:     map[string]string{"any-key": "any-value"},
+			},
+		}
+
+		t.Run("round trip", func(t *testing.T) {
+			s := NewBinarySerializer(WithVarint())
+
+			bs, err := s.Serialize(msg)
+			require.NoError(t, err)
+
+			var target testmodels.TestData
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, *msg, target)
+		})
+
+		t.Run("integer slices and maps", func(t *testing.T) {
+			s := NewBinarySerializer(WithVarint())
+
+			uints := &testmodels.Uint16SliceTestData{Uint16List: []uint16{0, 1, math.MaxUint16}}
+			bs, err := s.Serialize(uints)
+			require.NoError(t, err)
+
+			var uintsTarget testmodels.Uint16SliceTestData
+			err = s.Deserialize(bs, &uintsTarget)
+			require.NoError(t, err)
+			assert.Equal(t, *uints, uintsTarget)
+
+			ints := &testmodels.MapIntIntTestData{MapIntInt: map[int]int{-1: 1, 2: -2}}
+			bs, err = s.Serialize(ints)
+			require.NoError(t, err)
+
+			var intsTarget testmodels.MapIntIntTestData
+			err = s.Deserialize(bs, &intsTarget)
+			require.NoError(t, err)
+			assert.Equal(t, *ints, intsTarget)
+		})
+
+		t.Run("wire layout", func(t *testing.T) {
+			s := NewBinarySerializer(WithVarint())
+
+			bs, err := s.Serialize(int64(-1))
+			require.NoError(t, err)
+			assert.Equal(t, []byte{0x01}, bs)
+
+			bs, err = s.Serialize(uint64(300))
+			require.NoError(t, err)
+			assert.Equal(t, []byte{0xac, 0x02}, bs)
+
+			bs, err = s.Serialize([]int64{1, -2, 3})
+			require.NoError(t, err)
+			assert.Equal(t, []byte{0x03, 0x02, 0x03, 0x06}, bs)
+
+			bs, err = s.Serialize("abc")
+			require.NoError(t, err)
+			assert.Equal(t, []byte{0x03, 'a', 'b', 'c'}, bs)
+		})
+
+		t.Run("smaller than fixed width", func(t *testing.T) {
+			fixedBs, err := NewBinarySerializer().Serialize(msg)
+			require.NoError(t, err)
+
+			varintBs, err := NewBinarySerializer(WithVarint()).Serialize(msg)
+			require.NoError(t, err)
+			assert.Less(t, len(varintBs), len(fixedBs))
+		})
+
+		t.Run("every truncation fails without panicking", func(t *testing.T) {
+			s := NewBinarySerializer(WithVarint())
+
+			bs, err := s.Serialize(msg)
+			require.NoError(t, err)
+
+			for size := 0; size < len(bs); size++ {
+				var target testmodels.TestData
+				assert.NotPanics(t, func() {
+					err = s.Deserialize(bs[:size], &target)
+				})
+
+				var decodeErr *models.DecodeError
+				require.ErrorAs(t, err, &decodeErr, "size: %d", size)
+			}
+		})
+
+		t.Run("overflowing varint", func(t *testing.T) {
+			s := NewBinarySerializer(WithVarint())
+
+			var target int64
+			err := s.Deserialize(bytes.Repeat([]byte{0xff}, 11), &target)
+
+			var decodeErr *models.DecodeError
+			require.ErrorAs(t, err, &decodeErr)
+			assert.Equal(t, "varint overflows 64 bits", decodeErr.Reason)
+		})
+
+		t.Run("varint overflowing the field", func(t *testing.T) {
+			s := NewBinarySerializer(WithVarint())
+
+			bs, err := s.Serialize([]int64{math.MaxInt16 + 1})
+			require.NoError(t, err)
+
+			var target testmodels.Int16SliceTestData
+			err = s.Deserialize(bs, &target)
+
+			var decodeErr *models.DecodeError
+			require.ErrorAs(t, err, &decodeErr)
+			assert.Equal(t, "Int16List[0]", decodeErr.Field)
+			assert.Equal(t, "varint overflows int16", decodeErr.Reason)
+		})
+	})
+
+	t.Run("arrays", func(t *testing.T) {
+		msg := testmodels.ArrayTestData{
+			ID:       [16]byte{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8},
+			Counters: [4]int64{1, -2, math.MaxInt64, math.MinInt64},
+			Ratios:   [3]float64{0.5, -1.25, math.Pi},
+			Flags:    [3]bool{true, false, true},
+			Tags:     [2]string{"first", ""},
+			Matrix:   [2][3]int32{{1, 2, 3}, {-4, -5, -6}},
+			Items: [2]testmodels.Item{
+				{Id: "item-1", ItemId: 1, Number: 10, SubItem: &testmodels.SubItem{Date: 1}},
+				{Id: "item-2", ItemId: 2, Number: 20},
+			},
+			Codes:  [2]testmodels.Code{7, 42},
+			Chunks: [2][]byte{[]byte("chunk"), nil},
+			ByID:   map[[4]byte]int{{1, 2, 3, 4}: 1, {5, 6, 7, 8}: 2},
+		}
+		for i := range msg.Hash {
+			msg.Hash[i] = byte(i * 7)
+		}
+
+		t.Run("round trip", func(t *testing.T) {
+			for _, s := range []*BinarySerializer{NewBinarySerializer(), NewBinarySerializer(WithVarint())} {
+				bs, err := s.Serialize(&msg)
+				require.NoError(t, err)
+
+				var target testmodels.ArrayTestData
+				err = s.Deserialize(bs, &target)
+				require.NoError(t, err)
+				assert.Equal(t, msg, target)
+			}
+		})
+
+		t.Run("no length prefix", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(msg.ID)
+			require.NoError(t, err)
+			assert.Equal(t, msg.ID[:], bs)
+
+			bs, err = s.Serialize([2]int16{1, 2})
+			require.NoError(t, err)
+			assert.Len(t, bs, 4)
+
+			bs, err = NewBinarySerializer(WithVarint()).Serialize([3]int64{1, -1, 2})
+			require.NoError(t, err)
+			assert.Equal(t, []byte{0x02, 0x01, 0x04}, bs)
+		})
+
+		t.Run("top level array", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(msg.Hash)
+			require.NoError(t, err)
+
+			var target [32]uint8
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, msg.Hash, target)
+		})
+
+		t.Run("decoded array does not alias the payload", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(&msg)
+			require.NoError(t, err)
+
+			var target testmodels.ArrayTestData
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+
+			for i := range bs {
+				bs[i] = 0xff
+			}
+			assert.Equal(t, msg.ID, target.ID)
+			assert.Equal(t, msg.Counters, target.Counters)
+		})
+
+		t.Run("every truncation fails without panicking", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(&msg)
+			require.NoError(t, err)
+
+			for size := 0; size < len(bs); size++ {
+				var target testmodels.ArrayTestData
+				assert.NotPanics(t, func() {
+					err = s.Deserialize(bs[:size], &target)
+				})
+
+				var decodeErr *models.DecodeError
+				require.ErrorAs(t, err, &decodeErr, "size: %d", size)
+			}
+		})
+
+		t.Run("truncated element", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize([2]testmodels.Item{{Id: "item-1"}, {Id: "item-2"}})
+			require.NoError(t, err)
+
+			// cut within the second element's id
+			var target [2]testmodels.Item
+			err = s.Deserialize(bs[:len(bs)/2+6], &target)
+
+			var decodeErr *models.DecodeError
+			require.ErrorAs(t, err, &decodeErr)
+			assert.Equal(t, "[1].Id", decodeErr.Field)
+		})
+	})
+
+	t.Run("named types", func(t *testing.T) {
+		msg := testmodels.NamedTypesTestData{
+			Status:    "active",
+			Amount:    -1050,
+			Level:     3,
+			Ratio:     0.25,
+			Flag:      true,
+			IDs:       testmodels.IDs{1, 2, math.MaxInt64},
+			Tags:      testmodels.Tags{"a", "", "c"},
+			Statuses:  []testmodels.Status{"active", "deleted"},
+			Amounts:   []testmodels.Amount{-1, 0, 1},
+			Levels:    []testmodels.Level{1, math.MaxUint16},
+			Ratios:    []testmodels.Ratio{0.5, -2},
+			Flags:     []testmodels.Flag{true, false},
+			Blob:      testmodels.Blob("blob"),
+			Blobs:     testmodels.Blobs{[]byte("first"), []byte("second")},
+			BlobList:  []testmodels.Blob{testmodels.Blob("third")},
+			Codes:     []testmodels.Code{7, 42},
+			Durations: []time.Duration{time.Second, -time.Millisecond},
+			Window:    [3]testmodels.Amount{10, 20, 30},
+			ByStatus:  map[testmodels.Status]testmodels.Amount{"active": 1, "deleted": -1},
+		}
+
+		t.Run("round trip", func(t *testing.T) {
+			for _, s := range []*BinarySerializer{NewBinarySerializer(), NewBinarySerializer(WithVarint())} {
+				bs, err := s.Serialize(&msg)
+				require.NoError(t, err)
+
+				var target testmodels.NamedTypesTestData
+				err = s.Deserialize(bs, &target)
+				require.NoError(t, err)
+				assert.Equal(t, msg, target)
+			}
+		})
+
+		t.Run("same wire format as the predeclared types", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			for _, pair := range [][2]interface{}{
+				{testmodels.IDs{1, 2, 3}, []int64{1, 2, 3}},
+				{[]testmodels.Amount{1, 2, 3}, []int64{1, 2, 3}},
+				{testmodels.Tags{"a", "b"}, []string{"a", "b"}},
+				{[]testmodels.Status{"a", "b"}, []string{"a", "b"}},
+				{[]testmodels.Flag{true, false}, []bool{true, false}},
+				{testmodels.Blobs{[]byte("a")}, [][]byte{[]byte("a")}},
+				{testmodels.Status("active"), "active"},
+				{testmodels.Amount(-5), int64(-5)},
+			} {
+				named, err := s.Serialize(pair[0])
+				require.NoError(t, err)
+
+				predeclared, err := s.Serialize(pair[1])
+				require.NoError(t, err)
+				assert.Equal(t, predeclared, named, "%T", pair[0])
+			}
+		})
+
+		t.Run("elements with marshalers keep their hooks", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize([]testmodels.Code{7})
+			require.NoError(t, err)
+			assert.Equal(t, []byte{1, 0, 0, 0, 3, 0, 0, 0, 'C', '-', '7'}, bs)
+		})
+
+		t.Run("interface elements", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			msg := testmodels.InterfaceSliceTestData{
+				Errors: []error{nil, nil},
+				Values: []interface{}{"any", int64(-1), nil},
+			}
+
+			bs, err := s.Serialize(&msg)
+			require.NoError(t, err)
+
+			var target testmodels.InterfaceSliceTestData
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, msg, target)
+
+			bs, err = s.Serialize([]error{nil})
+			require.NoError(t, err)
+			assert.Equal(t, []byte{1, 0, 0, 0, 0, 0, 0, 0}, bs)
+		})
+
+		t.Run("top level named slice", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(msg.Amounts)
+			require.NoError(t, err)
+
+			var target []testmodels.Amount
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, msg.Amounts, target)
+		})
+	})
+
+	t.Run("envelope", func(t *testing.T) {
+		msg := &testmodels.Item{Id: "item-1", ItemId: 1, Number: 10, SubItem: &testmodels.SubItem{Date: 1}}
+
+		t.Run("round trip", func(t *testing.T) {
+			s := NewBinarySerializer(WithEnvelope())
+
+			bs, err := s.Serialize(msg)
+			require.NoError(t, err)
+			assert.Equal(t, []byte{'G', 'S', 'B', 1, 1, 0}, bs[:6])
+
+			var target testmodels.Item
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, *msg, target)
+		})
+
+		t.Run("varint flag", func(t *testing.T) {
+			bs, err := NewBinarySerializer(WithEnvelope(), WithVarint()).Serialize(msg)
+			require.NoError(t, err)
+			assert.Equal(t, []byte{'G', 'S', 'B', 1, 1, 1}, bs[:6])
+
+			// the header tells the fixed width serializer to read varints
+			var target testmodels.Item
+			err = NewBinarySerializer(WithEnvelope()).Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, *msg, target)
+		})
+
+		t.Run("payload written by another serializer", func(t *testing.T) {
+			bs, err := NewRawBinarySerializer(WithEnvelope()).Serialize(msg)
+			require.NoError(t, err)
+
+			var target testmodels.Item
+			err = NewBinarySerializer(WithEnvelope()).Deserialize(bs, &target)
+
+			var envelopeErr *models.EnvelopeError
+			require.ErrorAs(t, err, &envelopeErr)
+			assert.ErrorIs(t, err, models.ErrFormatMismatch)
+		})
+
+		t.Run("unsupported version", func(t *testing.T) {
+			s := NewBinarySerializer(WithEnvelope())
+
+			bs, err := s.Serialize(msg)
+			require.NoError(t, err)
+			bs[4] = 2
+
+			var target testmodels.Item
+			err = s.Deserialize(bs, &target)
+			assert.ErrorIs(t, err, models.ErrUnsupportedVersion)
+			assert.Contains(t, err.Error(), "got version 2, want 1")
+		})
+
+		t.Run("unsupported flags", func(t *testing.T) {
+			s := NewBinarySerializer(WithEnvelope())
+
+			bs, err := s.Serialize(msg)
+			require.NoError(t, err)
+			bs[5] |= 0x80
+
+			var target testmodels.Item
+			err = s.Deserialize(bs, &target)
+			assert.ErrorIs(t, err, models.ErrUnsupportedFlags)
+		})
+
+		t.Run("missing envelope", func(t *testing.T) {
+			s := NewBinarySerializer(WithEnvelope())
+
+			bs, err := NewBinarySerializer().Serialize(msg)
+			require.NoError(t, err)
+
+			for _, payload := range [][]byte{bs, nil, {'G', 'S'}} {
+				var target testmodels.Item
+				err = s.Deserialize(payload, &target)
+				assert.ErrorIs(t, err, models.ErrNotEnveloped)
+			}
+		})
+
+		t.Run("truncated header", func(t *testing.T) {
+			s := NewBinarySerializer(WithEnvelope())
+
+			bs, err := s.Serialize(msg)
+			require.NoError(t, err)
+
+			var target testmodels.Item
+			err = s.Deserialize(bs[:4], &target)
+
+			var decodeErr *models.DecodeError
+			require.ErrorAs(t, err, &decodeErr)
+			assert.Equal(t, 0, decodeErr.Offset)
+		})
+
+		t.Run("data rebind", func(t *testing.T) {
+			var target testmodels.Item
+			err := NewBinarySerializer(WithEnvelope()).DataRebind(msg, &target)
+			require.NoError(t, err)
+			assert.Equal(t, *msg, target)
+		})
+	})
+
+	t.Run("field ids", func(t *testing.T) {
+		v1 := testmodels.EventV1{
+			ID:     "event-1",
+			Kind:   3,
+			Amount: -1500,
+			Tags:   []string{"billing", "eu"},
+			Lines:  []testmodels.EventLine{{SKU: "sku-1", Quantity: 2}, {SKU: "sku-2", Quantity: -1}},
+			Parent: &testmodels.EventLine{SKU: "parent"},
+		}
+
+		t.Run("round trip", func(t *testing.T) {
+			for _, s := range []*BinarySerializer{
+				NewBinarySerializer(WithFieldIDs()),
+				NewBinarySerializer(WithFieldIDs(), WithVarint()),
+			} {
+				bs, err := s.Serialize(&v1)
+				require.NoError(t, err)
+
+				var target testmodels.EventV1
+				err = s.Deserialize(bs, &target)
+				require.NoError(t, err)
+				assert.Equal(t, v1, target)
+			}
+		})
+
+		t.Run("wire layout", func(t *testing.T) {
+			bs, err := NewBinarySerializer(WithFieldIDs()).Serialize(testmodels.EventLine{SKU: "a", Quantity: 2})
+			require.NoError(t, err)
+			assert.Equal(t, []byte{
+				1<<3 | 5, 5, 0, 0, 0, 1, 0, 0, 0, 'a', // SKU: bytes wire type, length, string
+				2<<3 | 3, 2, 0, 0, 0, // Quantity: fixed32 wire type
+				0, // end of fields
+			}, bs)
+		})
+
+		t.Run("newer reader", func(t *testing.T) {
+			for _, s := range []*BinarySerializer{
+				NewBinarySerializer(WithFieldIDs()),
+				NewBinarySerializer(WithFieldIDs(), WithVarint()),
+			} {
+				bs, err := s.Serialize(&v1)
+				require.NoError(t, err)
+
+				var target testmodels.EventV2
+				err = s.Deserialize(bs, &target)
+				require.NoError(t, err)
+				assert.Equal(t, testmodels.EventV2{
+					Lines:  v1.Lines,
+					ID:     v1.ID,
+					Tags:   v1.Tags,
+					Kind:   v1.Kind,
+					Parent: v1.Parent,
+				}, target)
+			}
+		})
+
+		t.Run("older reader", func(t *testing.T) {
+			s := NewBinarySerializer(WithFieldIDs())
+
+			bs, err := s.Serialize(testmodels.EventV2{ID: "event-2", Source: "api", Ratio: 0.5, Kind: 1})
+			require.NoError(t, err)
+
+			var target testmodels.EventV1
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, testmodels.EventV1{ID: "event-2", Kind: 1}, target)
+		})
+
+		t.Run("reused target", func(t *testing.T) {
+			s := NewBinarySerializer(WithFieldIDs())
+
+			bs, err := s.Serialize(&v1)
+			require.NoError(t, err)
+
+			target := testmodels.EventV2{
+				ID:     "stale",
+				Source: "stale",
+				Ratio:  0.25,
+				Parent: &testmodels.EventLine{SKU: "stale", Quantity: 7},
+			}
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, testmodels.EventV2{
+				Lines:  v1.Lines,
+				ID:     v1.ID,
+				Tags:   v1.Tags,
+				Kind:   v1.Kind,
+				Parent: v1.Parent,
+			}, target)
+		})
+
+		t.Run("envelope flag", func(t *testing.T) {
+			bs, err := NewBinarySerializer(WithEnvelope(), WithFieldIDs()).Serialize(&v1)
+			require.NoError(t, err)
+
+			var target testmodels.EventV2
+			err = NewBinarySerializer(WithEnvelope()).Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, v1.ID, target.ID)
+			assert.Equal(t, v1.Lines, target.Lines)
+		})
+
+		t.Run("changed wire type", func(t *testing.T) {
+			s := NewBinarySerializer(WithFieldIDs())
+
+			bs, err := s.Serialize(&v1)
+			require.NoError(t, err)
+
+			var target testmodels.EventKindChanged
+			err = s.Deserialize(bs, &target)
+
+			var decodeErr *models.DecodeError
+			require.ErrorAs(t, err, &decodeErr)
+			assert.Equal(t, "Kind", decodeErr.Field)
+			assert.Equal(t, "field id 2 has wire type fixed8, want fixed64", decodeErr.Reason)
+		})
+
+		t.Run("missing id", func(t *testing.T) {
+			_, err := NewBinarySerializer(WithFieldIDs()).Serialize(testmodels.MissingFieldIDTestData{ID: "id"})
+
+			var tagErr *models.StructTagError
+			require.ErrorAs(t, err, &tagErr)
+			assert.Equal(t, "Name", tagErr.Field)
+		})
+
+		t.Run("duplicate id", func(t *testing.T) {
+			_, err := NewBinarySerializer(WithFieldIDs()).Serialize(testmodels.DuplicateFieldIDTestData{})
+
+			var tagErr *models.StructTagError
+			require.ErrorAs(t, err, &tagErr)
+			assert.Equal(t, "Name", tagErr.Field)
+			assert.Contains(t, tagErr.Error(), "id 1 already used by field ID")
+		})
+
+		t.Run("every truncation fails without panicking", func(t *testing.T) {
+			s := NewBinarySerializer(WithFieldIDs())
+
+			bs, err := s.Serialize(&v1)
+			require.NoError(t, err)
+
+			for size := 0; size < len(bs); size++ {
+				var target testmodels.EventV2
+				assert.NotPanics(t, func() {
+					err = s.Deserialize(bs[:size], &target)
+				})
+
+				var decodeErr *models.DecodeError
+				require.ErrorAs(t, err, &decodeErr, "size: %d", size)
+			}
+		})
+	})
+
+	t.Run("schema fingerprint", func(t *testing.T) {
+		item := testmodels.Item{Id: "item-1", ItemId: 1, Number: 10, SubItem: &testmodels.SubItem{Date: 1}}
+
+		t.Run("round trip", func(t *testing.T) {
+			s := NewBinarySerializer(WithSchemaFingerprint())
+
+			bs, err := s.Serialize(&item)
+			require.NoError(t, err)
+
+			var target testmodels.Item
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, item, target)
+
+			// a type renamed without changing its layout still matches
+			var renamed testmodels.RenamedItem
+			err = s.Deserialize(bs, &renamed)
+			require.NoError(t, err)
+			assert.Equal(t, item.Id, renamed.Id)
+		})
+
+		t.Run("mismatch", func(t *testing.T) {
+			s := NewBinarySerializer(WithSchemaFingerprint())
+
+			bs, err := s.Serialize(&item)
+			require.NoError(t, err)
+
+			var target testmodels.ReorderedItem
+			err = s.Deserialize(bs, &target)
+			require.ErrorIs(t, err, models.ErrSchemaMismatch)
+
+			var mismatchErr *models.SchemaMismatchError
+			require.ErrorAs(t, err, &mismatchErr)
+			assert.Equal(t, reflect.TypeOf(target), mismatchErr.Type)
+			assert.Equal(t, SchemaFingerprint(item), mismatchErr.Payload)
+			assert.Equal(t, testmodels.ReorderedItem{}, target)
+		})
+
+		t.Run("envelope flag", func(t *testing.T) {
+			bs, err := NewBinarySerializer(WithEnvelope(), WithSchemaFingerprint()).Serialize(&item)
+			require.NoError(t, err)
+			assert.Equal(t, byte(0x10), bs[5])
+
+			var target testmodels.ReorderedItem
+			err = NewBinarySerializer(WithEnvelope()).Deserialize(bs, &target)
+			require.ErrorIs(t, err, models.ErrSchemaMismatch)
+		})
+
+		t.Run("truncated fingerprint", func(t *testing.T) {
+			var target testmodels.Item
+			err := NewBinarySerializer(WithSchemaFingerprint()).Deserialize([]byte{1, 2, 3}, &target)
+
+			var decodeErr *models.DecodeError
+			require.ErrorAs(t, err, &decodeErr)
+		})
+
+		t.Run("data rebind", func(t *testing.T) {
+			var target testmodels.RenamedItem
+			err := NewBinarySerializer(WithSchemaFingerprint()).DataRebind(&item, &target)
+			require.NoError(t, err)
+			assert.Equal(t, item.Number, target.Number)
+		})
+	})
+
+	t.Run("checksum", func(t *testing.T) {
+		item := testmodels.Item{Id: "item-1", ItemId: 1, Number: 10, SubItem: &testmodels.SubItem{Date: 1}}
+
+		t.Run("round trip", func(t *testing.T) {
+			s := NewBinarySerializer(WithChecksum())
+
+			bs, err := s.Serialize(&item)
+			require.NoError(t, err)
+
+			plain, err := NewBinarySerializer().Serialize(&item)
+			require.NoError(t, err)
+			assert.Equal(t, plain, bs[:len(bs)-4])
+
+			var target testmodels.Item
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, item, target)
+		})
+
+		t.Run("marshal", func(t *testing.T) {
+			s := NewBinarySerializer(WithChecksum())
+
+			bs, err := s.Marshal(&item)
+			require.NoError(t, err)
+
+			var target testmodels.Item
+			err = s.Unmarshal(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, item, target)
+
+			bs[0] ^= 0xff
+			err = s.Unmarshal(bs, &target)
+			assert.ErrorIs(t, err, models.ErrChecksumMismatch)
+		})
+
+		t.Run("every bit flip is detected", func(t *testing.T) {
+			s := NewBinarySerializer(WithChecksum(), WithEnvelope())
+
+			bs, err := s.Serialize(&item)
+			require.NoError(t, err)
+
+			for i := range bs {
+				// flipping the magic bytes, format or version is reported by the envelope instead
+				if i < 5 {
+					continue
+				}
+
+				for bit := 0; bit < 8; bit++ {
+					corrupted := bytes.Clone(bs)
+					corrupted[i] ^= 1 << bit
+
+					var target testmodels.Item
+					err = s.Deserialize(corrupted, &target)
+					assert.Error(t, err, "byte %d, bit %d", i, bit)
+				}
+			}
+		})
+
+		t.Run("every truncation is detected", func(t *testing.T) {
+			s := NewBinarySerializer(WithChecksum())
+
+			bs, err := s.Serialize(&item)
+			require.NoError(t, err)
+
+			for size := 0; size < len(bs); size++ {
+				var target testmodels.Item
+				err = s.Deserialize(bs[:size], &target)
+				assert.Error(t, err, "size: %d", size)
+
+				var checksumErr *models.ChecksumError
+				if size >= 4 {
+					assert.ErrorAs(t, err, &checksumErr, "size: %d", size)
+				}
+			}
+		})
+
+		t.Run("envelope flag", func(t *testing.T) {
+			bs, err := NewBinarySerializer(WithEnvelope(), WithChecksum()).Serialize(&item)
+			require.NoError(t, err)
+			assert.Equal(t, byte(0x02), bs[5])
+
+			var target testmodels.Item
+			err = NewBinarySerializer(WithEnvelope()).Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, item, target)
+		})
+	})
+
+	t.Run("append", func(t *testing.T) {
+		item := testmodels.Item{Id: "item-1", ItemId: 1, Number: 10, SubItem: &testmodels.SubItem{Date: 1}}
+
+		t.Run("keeps dst", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			plain, err := s.Serialize(&item)
+			require.NoError(t, err)
+
+			bs, err := s.Append([]byte("prefix"), &item)
+			require.NoError(t, err)
+			assert.Equal(t, append([]byte("prefix"), plain...), bs)
+
+			var target testmodels.Item
+			err = s.Deserialize(bs[len("prefix"):], &target)
+			require.NoError(t, err)
+			assert.Equal(t, item, target)
+		})
+
+		t.Run("writes into the spare capacity", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			dst := make([]byte, 3, 1<<10)
+			bs, err := s.Append(dst, &item)
+			require.NoError(t, err)
+			assert.Same(t, &dst[0], &bs[0])
+
+			allocs := testing.AllocsPerRun(100, func() {
+				_, _ = s.Append(dst, &item)
+			})
+			assert.Zero(t, allocs)
+		})
+
+		t.Run("grows", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			for _, dst := range [][]byte{nil, {}, make([]byte, 1, 1), make([]byte, 0, 2)} {
+				bs, err := s.Append(dst, &item)
+				require.NoError(t, err)
+
+				var target testmodels.Item
+				err = s.Deserialize(bs[len(dst):], &target)
+				require.NoError(t, err)
+				assert.Equal(t, item, target)
+			}
+		})
+
+		t.Run("successive values", func(t *testing.T) {
+			s := NewBinarySerializer(WithEnvelope(), WithSchemaFingerprint(), WithChecksum())
+
+			first, err := s.Serialize(&item)
+			require.NoError(t, err)
+
+			bs, err := s.Append(nil, &item)
+			require.NoError(t, err)
+			bs, err = s.Append(bs, &item)
+			require.NoError(t, err)
+			require.Len(t, bs, 2*len(first))
+
+			for _, payload := range [][]byte{bs[:len(first)], bs[len(first):]} {
+				assert.Equal(t, first, payload)
+
+				var target testmodels.Item
+				err = s.Deserialize(payload, &target)
+				require.NoError(t, err)
+				assert.Equal(t, item, target)
+			}
+		})
+
+		t.Run("errors", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			dst := []byte("prefix")
+			bs, err := s.Append(dst, make(chan int))
+			assert.Error(t, err)
+			assert.Equal(t, dst, bs)
+		})
+	})
+
+	t.Run("pooled buffers", func(t *testing.T) {
+		item := testmodels.Item{Id: "item-1", ItemId: 1, Number: 10, SubItem: &testmodels.SubItem{Date: 1}}
+
+		t.Run("payloads are owned by the caller", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			first, err := s.Serialize(&item)
+			require.NoError(t, err)
+			want := bytes.Clone(first)
+
+			other := testmodels.Item{Id: strings.Repeat("other", 100), ItemId: 2}
+			for i := 0; i < 10; i++ {
+				_, err = s.Serialize(&other)
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, want, first)
+		})
+
+		t.Run("serialize to", func(t *testing.T) {
+			s := NewBinarySerializer(WithEnvelope(), WithChecksum())
+
+			want, err := s.Serialize(&item)
+			require.NoError(t, err)
+
+			var got []byte
+			err = s.SerializeTo(&item, func(bs []byte) error {
+				got = bytes.Clone(bs)
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+
+			allocs := testing.AllocsPerRun(100, func() {
+				_ = s.SerializeTo(&item, func([]byte) error { return nil })
+			})
+			assert.Zero(t, allocs)
+		})
+
+		t.Run("serialize to errors", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			errCallback := errors.New("callback")
+			err := s.SerializeTo(&item, func([]byte) error { return errCallback })
+			assert.Equal(t, errCallback, err)
+
+			called := false
+			err = s.SerializeTo(make(chan int), func([]byte) error {
+				called = true
+				return nil
+			})
+			assert.Error(t, err)
+			assert.False(t, called)
+		})
+
+		t.Run("buffers outgrowing their class", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			for _, size := range []int{1, 1 << 10, 1 << 21, 1 << 4, 1 << 16, 1} {
+				msg := testmodels.Item{Id: strings.Repeat("x", size)}
+
+				bs, err := s.Serialize(&msg)
+				require.NoError(t, err)
+
+				var target testmodels.Item
+				err = s.Deserialize(bs, &target)
+				require.NoError(t, err)
+				assert.Equal(t, msg, target)
+			}
+		})
+
+		t.Run("concurrency", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			var wg sync.WaitGroup
+			for g := 0; g < 8; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+
+					for i := 0; i < 100; i++ {
+						msg := testmodels.Item{Id: strings.Repeat("x", g*i), ItemId: uint64(g)}
+
+						bs, err := s.Serialize(&msg)
+						assert.NoError(t, err)
+
+						var target testmodels.Item
+						assert.NoError(t, s.Deserialize(bs, &target))
+						assert.Equal(t, msg, target)
+					}
+				}(g)
+			}
+
+			wg.Wait()
+		})
+	})
+	t.Run("type plans", func(t *testing.T) {
+		tree := &testmodels.TreeNode{
+			Value: 1,
+			Children: []*testmodels.TreeNode{
+				{Value: 2, Children: []*testmodels.TreeNode{{Value: 4}, {Value: 5}}},
+				{Value: 3},
+				nil,
+			},
+		}
+
+		t.Run("recursive types", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			bs, err := s.Serialize(tree)
+			require.NoError(t, err)
+
+			var target testmodels.TreeNode
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, tree, &target)
+		})
+
+		t.Run("plans per options", func(t *testing.T) {
+			fixed, varint := NewBinarySerializer(), NewBinarySerializer(WithVarint())
+
+			fixedBs, err := fixed.Serialize(tree)
+			require.NoError(t, err)
+			varintBs, err := varint.Serialize(tree)
+			require.NoError(t, err)
+			assert.Less(t, len(varintBs), len(fixedBs))
+
+			var fixedTarget, varintTarget testmodels.TreeNode
+			require.NoError(t, fixed.Deserialize(fixedBs, &fixedTarget))
+			require.NoError(t, varint.Deserialize(varintBs, &varintTarget))
+			assert.Equal(t, tree, &fixedTarget)
+			assert.Equal(t, tree, &varintTarget)
+		})
+
+		t.Run("map entries are decoded into distinct values", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			msg := map[string]testmodels.Item{
+				"first":  {Id: "first", SubItem: &testmodels.SubItem{ItemCode: "first-code"}},
+				"second": {Id: "second", Number: 2},
+				"third":  {Id: "third", SubItem: &testmodels.SubItem{ItemCode: "third-code"}},
+			}
+
+			bs, err := s.Serialize(msg)
+			require.NoError(t, err)
+
+			var target map[string]testmodels.Item
+			err = s.Deserialize(bs, &target)
+			require.NoError(t, err)
+			assert.Equal(t, msg, target)
+			assert.NotSame(t, target["first"].SubItem, target["third"].SubItem)
+		})
+
+		t.Run("concurrency", func(t *testing.T) {
+			var wg sync.WaitGroup
+			for g := 0; g < 8; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+
+					s := NewBinarySerializer()
+					if g%2 == 1 {
+						s = NewBinarySerializer(WithVarint())
+					}
+
+					for i := 0; i < 50; i++ {
+						bs, err := s.Serialize(tree)
+						assert.NoError(t, err)
+
+						var target testmodels.TreeNode
+						assert.NoError(t, s.Deserialize(bs, &target))
+						assert.Equal(t, tree, &target)
+					}
+				}(g)
+			}
+
+			wg.Wait()
+		})
+	})
+	t.Run("typed api", func(t *testing.T) {
+		item := testmodels.Item{
+			Id:      "item",
+			ItemId:  7,
+			Number:  -7,
+			SubItem: &testmodels.SubItem{Date: 1, Amount: 2, ItemCode: "code"},
+		}
+
+		t.Run("nil pointers", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			bs, err := Encode[*testmodels.Item](s, nil)
+			require.NoError(t, err)
+			assert.Empty(t, bs)
+
+			bs, err = NewCodec[*testmodels.Item](s).Encode(nil)
+			require.NoError(t, err)
+			assert.Empty(t, bs)
+		})
+
+		t.Run("interface types", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			want, err := s.Serialize(item)
+			require.NoError(t, err)
+
+			bs, err := Encode[interface{}](s, item)
+			require.NoError(t, err)
+			assert.Equal(t, want, bs)
+		})
+
+		t.Run("payload written in another layout", func(t *testing.T) {
+			bs, err := Encode(NewBinarySerializer(WithEnvelope(), WithVarint()), item)
+			require.NoError(t, err)
+
+			decoded, err := Decode[testmodels.Item](NewBinarySerializer(WithEnvelope()), bs)
+			require.NoError(t, err)
+			assert.Equal(t, item, decoded)
+		})
+
+		t.Run("errors", func(t *testing.T) {
+			s := NewBinarySerializer()
+
+			bs, err := Encode(s, item)
+			require.NoError(t, err)
+
+			decoded, err := Decode[testmodels.Item](s, bs[:len(bs)-1])
+			assert.Error(t, err)
+			assert.Zero(t, decoded)
+
+			_, err = Encode(s, make(chan int))
+			var unsupported *models.UnsupportedTypeError
+			assert.ErrorAs(t, err, &unsupported)
+		})
+
+		t.Run("concurrency", func(t *testing.T) {
+			s := NewBinarySerializer()
+			c := NewCodec[*testmodels.Item](s)
+
+			var wg sync.WaitGroup
+			for g := 0; g < 8; g++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					for i := 0; i < 50; i++ {
+						bs, err := c.Encode(&item)
+						assert.NoError(t, err)
+
+						decoded, err := Decode[*testmodels.Item](s, bs)
+						assert.NoError(t, err)
+						assert.Equal(t, &item, decoded)
+					}
+				}()
+			}
+
+			wg.Wait()
+		})
+	})
+	t.Run("zero copy", func(t *testing.T) {
+		newMsg := func() *testmodels.ZeroCopyTestData {
+			return &testmodels.ZeroCopyTestData{
+				String:  "string",
+				Bytes:   []byte("bytes"),
+				Chunks:  [][]byte{[]byte("first"), []byte("second")},
+				Strings: []string{"a", "bc"},
+				Labels:  map[string]string{"key": "value"},
+				Any:     map[string]interface{}{"bytes": []byte("any"), "string": "any"},
+				Int64s:  []int64{1, -1},
+			}
+		}
+
+		// decodeThenOverwrite decodes a payload of newMsg and overwrites the payload, as a reused read buffer would be.
+		decodeThenOverwrite := func(t *testing.T, s *BinarySerializer) *testmodels.ZeroCopyTestData {
+			bs, err := s.Serialize(newMsg())
+			require.NoError(t, err)
+
+			var target testmodels.ZeroCopyTestData
+			require.NoError(t, s.Deserialize(bs, &target))
+
+			for i := range bs {
+				bs[i] = 0xff
+			}
+
+			return &target
+		}
+
+		t.Run("copies never alias the payload", func(t *testing.T) {
+			s := NewBinarySerializer(WithZeroCopy(false))
+			assert.Equal(t, newMsg(), decodeThenOverwrite(t, s))
+
+			typed, err := Encode(s, newMsg())
+			require.NoError(t, err)
+
+			decoded, err := Decode[*testmodels.ZeroCopyTestData](s, typed)
+			require.NoError(t, err)
+
+			for i := range typed {
+				typed[i] = 0xff
+			}
+			assert.Equal(t, newMsg(), decoded)
+		})
+
+		t.Run("zero copy aliases the payload", func(t *testing.T) {
+			target := decodeThenOverwrite(t, NewBinarySerializer(WithZeroCopy(true)))
+			assert.Equal(t, strings.Repeat("\xff", len("string")), target.String)
+			assert.Equal(t, bytes.Repeat([]byte{0xff}, len("bytes")), target.Bytes)
+			assert.Equal(t, bytes.Repeat([]byte{0xff}, len("first")), target.Chunks[0])
+		})
+
+		t.Run("default", func(t *testing.T) {
+			target := decodeThenOverwrite(t, NewBinarySerializer())
+			assert.Equal(t, "string", target.String)
+			assert.Equal(t, "bytes", string(target.Bytes))
+		})
+
+		t.Run("payload kept by DataRebind", func(t *testing.T) {
+			var target testmodels.ZeroCopyTestData
+			require.NoError(t, NewBinarySerializer(WithZeroCopy(true)).DataRebind(newMsg(), &target))
+			assert.Equal(t, newMsg(), &target)
+		})
+	})
 }
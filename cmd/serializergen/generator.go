@@ -0,0 +1,652 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	serializerPath = "gitlab.com/pietroski-software-company/devex/golang/serializer"
+	// tagName and maxFieldID mirror the struct tags read by the binary serializers.
+	tagName    = "binary"
+	maxFieldID = 1<<29 - 1
+)
+
+// generate returns the source of the methods of the struct types named typeNames, declared by the package in dir.
+// Type errors in outputName are ignored, as the file is about to be replaced.
+func generate(dir string, typeNames []string, outputName string) ([]byte, error) {
+	// the packages are type-checked from source, which does not depend on the export data format of the toolchain
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps |
+			packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesSizes,
+		Dir: dir,
+	}, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("%d packages found in %s", len(pkgs), dir)
+	}
+
+	pkg := pkgs[0]
+	outputName, _ = filepath.Abs(outputName)
+	for _, pkgErr := range pkg.Errors {
+		if file, _, _ := strings.Cut(pkgErr.Pos, ":"); file != outputName {
+			return nil, pkgErr
+		}
+	}
+
+	g := &generator{
+		pkg:     pkg.Types,
+		sizes:   pkg.TypesSizes,
+		structs: make(map[*types.Named]bool),
+		imports: make(map[string]string),
+	}
+
+	named := make([]*types.Named, 0, len(typeNames))
+	for _, name := range typeNames {
+		obj, ok := pkg.Types.Scope().Lookup(name).(*types.TypeName)
+		if !ok {
+			return nil, fmt.Errorf("type %s not found in package %s", name, pkg.PkgPath)
+		}
+
+		t, ok := types.Unalias(obj.Type()).(*types.Named)
+		if !ok || t.Obj().Pkg() != pkg.Types {
+			return nil, fmt.Errorf("%s is not a type defined by package %s", name, pkg.PkgPath)
+		}
+
+		if _, ok = t.Underlying().(*types.Struct); !ok {
+			return nil, fmt.Errorf("%s is not a struct type", name)
+		}
+
+		if t.TypeParams().Len() > 0 {
+			return nil, fmt.Errorf("%s is generic, which is not supported", name)
+		}
+
+		if hasMarshaler(t) {
+			return nil, fmt.Errorf("%s is serialized through its marshaler methods", name)
+		}
+
+		g.structs[t] = true
+		named = append(named, t)
+	}
+
+	for _, t := range named {
+		if err = g.generateType(t); err != nil {
+			return nil, err
+		}
+	}
+
+	return g.source()
+}
+
+// hasMarshaler reports whether the binary serializers would serialize t through its marshaler methods.
+func hasMarshaler(t types.Type) bool {
+	methods := types.NewMethodSet(types.NewPointer(t))
+	has := func(name string) bool {
+		return methods.Lookup(nil, name) != nil
+	}
+
+	return (has("MarshalBinary") && has("UnmarshalBinary")) || (has("MarshalText") && has("UnmarshalText"))
+}
+
+// generator writes the methods of a set of struct types declared by pkg.
+type generator struct {
+	pkg     *types.Package
+	sizes   types.Sizes
+	structs map[*types.Named]bool
+	// imports maps the paths imported by the generated code to their names.
+	imports map[string]string
+
+	buf bytes.Buffer
+	// vars counts the variables declared by the method being generated, to keep their names unique.
+	vars int
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// newVar returns a variable name starting with prefix that is unique within the method being generated.
+func (g *generator) newVar(prefix string) string {
+	g.vars++
+	return prefix + strconv.Itoa(g.vars)
+}
+
+// qualifier names the packages of the types written in the generated code, importing them.
+func (g *generator) qualifier(pkg *types.Package) string {
+	if pkg == g.pkg {
+		return ""
+	}
+
+	g.imports[pkg.Path()] = pkg.Name()
+	return pkg.Name()
+}
+
+// typeString returns t as written in the generated code.
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, g.qualifier)
+}
+
+// serializer returns name qualified by the serializer package.
+func (g *generator) serializer(name string) string {
+	if g.pkg.Path() == serializerPath {
+		return name
+	}
+
+	g.imports[serializerPath] = "serializer"
+	return "serializer." + name
+}
+
+// field is a struct field in wire order.
+type field struct {
+	name  string
+	typ   types.Type
+	order int
+}
+
+// fields returns the fields of t the binary serializers write with their default options, in wire order.
+func (g *generator) fields(t *types.Named) ([]field, error) {
+	st := t.Underlying().(*types.Struct)
+
+	fields := make([]field, 0, st.NumFields())
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		tag, hasTag := reflect.StructTag(st.Tag(i)).Lookup(tagName)
+		if tag == "-" {
+			continue
+		}
+
+		f := field{name: v.Name(), typ: v.Type(), order: -1}
+		if hasTag {
+			order, err := parseTag(tag)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: invalid %s tag %q: %w", t.Obj().Name(), v.Name(), tagName, tag, err)
+			}
+
+			f.order = order
+		}
+
+		// unexported fields are still sorted, as they are by the serializers, then left out
+		if !v.Exported() {
+			f.name = ""
+		}
+
+		fields = append(fields, f)
+	}
+
+	sort.SliceStable(fields, func(i, j int) bool {
+		if fields[i].order < 0 || fields[j].order < 0 {
+			return fields[i].order >= 0 && fields[j].order < 0
+		}
+
+		return fields[i].order < fields[j].order
+	})
+
+	exported := fields[:0]
+	for _, f := range fields {
+		if f.name != "" {
+			exported = append(exported, f)
+		}
+	}
+
+	return exported, nil
+}
+
+// parseTag returns the order option of a binary struct tag, or -1, validating the other options.
+func parseTag(tag string) (int, error) {
+	order := -1
+
+	_, opts, _ := strings.Cut(tag, ",")
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+
+		key, value, _ := strings.Cut(opt, "=")
+		switch key {
+		case "order":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid order %q", value)
+			}
+
+			order = n
+		case "id":
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 || n > maxFieldID {
+				return 0, fmt.Errorf("invalid id %q", value)
+			}
+		default:
+			return 0, fmt.Errorf("unknown option %q", key)
+		}
+	}
+
+	return order, nil
+}
+
+// generateType writes the MarshalBinaryTo and UnmarshalBinaryFrom methods of t.
+func (g *generator) generateType(t *types.Named) error {
+	fields, err := g.fields(t)
+	if err != nil {
+		return err
+	}
+
+	name := t.Obj().Name()
+
+	g.vars = 0
+	g.printf("// MarshalBinaryTo writes x in the layout of serializer.BinarySerializer.\n")
+	g.printf("func (x *%s) MarshalBinaryTo(w *%s) error {\n", name, g.serializer("Writer"))
+	for _, f := range fields {
+		if err = g.encode("x."+f.name, f.typ); err != nil {
+			return fmt.Errorf("%s.%s: %w", name, f.name, err)
+		}
+	}
+	g.printf("return nil\n}\n\n")
+
+	g.vars = 0
+	g.printf("// UnmarshalBinaryFrom reads x from the layout of serializer.BinarySerializer.\n")
+	g.printf("func (x *%s) UnmarshalBinaryFrom(r *%s) error {\n", name, g.serializer("Reader"))
+	for _, f := range fields {
+		if err = g.decode("x."+f.name, f.typ); err != nil {
+			return fmt.Errorf("%s.%s: %w", name, f.name, err)
+		}
+	}
+	g.printf("return nil\n}\n\n")
+
+	return nil
+}
+
+// source returns the formatted source of the generated file.
+func (g *generator) source() ([]byte, error) {
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by serializergen; DO NOT EDIT.\n\npackage %s\n\n", g.pkg.Name())
+
+	paths := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	src.WriteString("import (\n")
+	// the standard library first, the other imports after a blank line
+	for _, std := range []bool{true, false} {
+		for _, path := range paths {
+			if isStd := !strings.Contains(strings.Split(path, "/")[0], "."); isStd != std {
+				continue
+			}
+
+			if name := g.imports[path]; name != filepath.Base(path) {
+				fmt.Fprintf(&src, "%s %q\n", name, path)
+			} else {
+				fmt.Fprintf(&src, "%q\n", path)
+			}
+		}
+		src.WriteString("\n")
+	}
+	src.WriteString(")\n\n")
+
+	src.Write(g.buf.Bytes())
+	return format.Source(src.Bytes())
+}
+
+// ################################################################################################################## \\
+// type walk
+// ################################################################################################################## \\
+
+// kind tells how the generated code handles a type.
+type kind uint8
+
+const (
+	// inline types are written and read by the generated code itself.
+	inline kind = iota
+	// generated types are the structs whose methods are being generated.
+	generated
+	// delegated types are handed over to Writer.WriteValue and Reader.ReadValue.
+	delegated
+)
+
+// kindOf returns how the generated code handles t, failing for the types the binary serializers do not support.
+func (g *generator) kindOf(t types.Type) (kind, error) {
+	switch t := types.Unalias(t).(type) {
+	case *types.Named:
+		switch {
+		case g.structs[t]:
+			return generated, nil
+		case t.Obj().Pkg() != g.pkg, t.TypeArgs().Len() > 0, hasMarshaler(t):
+			// types from other packages may have codecs, which only the serializers know about
+			return delegated, nil
+		}
+
+		if _, ok := t.Underlying().(*types.Struct); ok {
+			return delegated, nil
+		}
+
+		return g.kindOf(t.Underlying())
+	case *types.Basic:
+		switch t.Kind() {
+		case types.Uintptr, types.UnsafePointer:
+			return 0, fmt.Errorf("unsupported type %s", t)
+		default:
+			return inline, nil
+		}
+	case *types.Pointer, *types.Slice, *types.Array, *types.Map:
+		return inline, nil
+	case *types.Interface:
+		return delegated, nil
+	case *types.Struct:
+		return 0, fmt.Errorf("anonymous struct types are not supported")
+	default:
+		return 0, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+// isByte reports whether t is byte itself, whose slices and arrays are written at once.
+func isByte(t types.Type) bool {
+	return types.Identical(t, types.Typ[types.Byte])
+}
+
+// minSize returns the least number of bytes a value of t takes in the payload, like the serializers reckon it
+// before allocating the elements of slices and maps: one when the values of t take memory, zero otherwise.
+func (g *generator) minSize(ts ...types.Type) int {
+	for _, t := range ts {
+		if g.sizes.Sizeof(t) > 0 {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// operand returns expr as the operand of an index, slice or selector expression.
+func operand(expr string) string {
+	if strings.HasPrefix(expr, "*") {
+		return "(" + expr + ")"
+	}
+
+	return expr
+}
+
+// receiver returns expr as a method receiver or a pointer to it, expr being addressable.
+func receiver(expr string) string {
+	if deref, ok := strings.CutPrefix(expr, "*"); ok {
+		return deref
+	}
+
+	return expr
+}
+
+// address returns a pointer to the addressable expr.
+func address(expr string) string {
+	if deref, ok := strings.CutPrefix(expr, "*"); ok {
+		return deref
+	}
+
+	return "&" + expr
+}
+
+// byteSlice is the type of the slices Writer.WriteBytes and Reader.ReadBytes handle.
+var byteSlice = types.NewSlice(types.Typ[types.Byte])
+
+// convert returns expr, of type from, converted to t unless both types are identical.
+func (g *generator) convert(expr string, t, from types.Type) string {
+	if types.Identical(t, from) {
+		return expr
+	}
+
+	return g.typeString(t) + "(" + expr + ")"
+}
+
+// as returns expr, of type t, converted to the basic type the Writer method taking it expects.
+func (g *generator) as(expr string, t types.Type, basic types.BasicKind) string {
+	return g.convert(expr, types.Typ[basic], t)
+}
+
+// ################################################################################################################## \\
+// encoder
+// ################################################################################################################## \\
+
+// encode writes the statements writing the addressable expr of type t.
+func (g *generator) encode(expr string, t types.Type) error {
+	k, err := g.kindOf(t)
+	if err != nil {
+		return err
+	}
+
+	switch k {
+	case generated:
+		g.printf("if err := %s.MarshalBinaryTo(w); err != nil {\nreturn err\n}\n", receiver(expr))
+		return nil
+	case delegated:
+		g.printf("if err := w.WriteValue(%s); err != nil {\nreturn err\n}\n", address(expr))
+		return nil
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		return g.encodeBasic(expr, t, u)
+	case *types.Pointer:
+		g.printf("if %s == nil {\nw.WriteBool(true)\n} else {\nw.WriteBool(false)\n", expr)
+		if err = g.encode("*"+operand(expr), u.Elem()); err != nil {
+			return err
+		}
+		g.printf("}\n")
+	case *types.Slice:
+		if isByte(u.Elem()) {
+			g.printf("w.WriteBytes(%s)\n", g.convert(expr, byteSlice, t))
+			return nil
+		}
+
+		i := g.newVar("i")
+		g.printf("w.WriteUint32(uint32(len(%s)))\n", expr)
+		g.printf("for %s := range %s {\n", i, expr)
+		if err = g.encode(operand(expr)+"["+i+"]", u.Elem()); err != nil {
+			return err
+		}
+		g.printf("}\n")
+	case *types.Array:
+		if isByte(u.Elem()) {
+			g.printf("w.Write(%s[:])\n", operand(expr))
+			return nil
+		}
+
+		i := g.newVar("i")
+		g.printf("for %s := range %s {\n", i, expr)
+		if err = g.encode(operand(expr)+"["+i+"]", u.Elem()); err != nil {
+			return err
+		}
+		g.printf("}\n")
+	case *types.Map:
+		k, v := g.newVar("k"), g.newVar("v")
+		g.printf("w.WriteUint32(uint32(len(%s)))\n", expr)
+		g.printf("for %s, %s := range %s {\n", k, v, expr)
+		if err = g.encode(k, u.Key()); err != nil {
+			return err
+		}
+		if err = g.encode(v, u.Elem()); err != nil {
+			return err
+		}
+		g.printf("}\n")
+	}
+
+	return nil
+}
+
+// encodeBasic writes the statements writing expr of type t, whose underlying type is u.
+func (g *generator) encodeBasic(expr string, t types.Type, u *types.Basic) error {
+	switch u.Kind() {
+	case types.Bool:
+		g.printf("w.WriteBool(%s)\n", g.as(expr, t, types.Bool))
+	case types.String:
+		g.printf("w.WriteString(%s)\n", g.as(expr, t, types.String))
+	case types.Int, types.Int64, types.Uint, types.Uint64:
+		g.printf("w.WriteUint64(%s)\n", g.as(expr, t, types.Uint64))
+	case types.Int32, types.Uint32:
+		g.printf("w.WriteUint32(%s)\n", g.as(expr, t, types.Uint32))
+	case types.Int16, types.Uint16:
+		g.printf("w.WriteUint16(%s)\n", g.as(expr, t, types.Uint16))
+	case types.Int8, types.Uint8:
+		g.printf("w.WriteByte(%s)\n", g.as(expr, t, types.Byte))
+	case types.Float32:
+		g.imports["math"] = "math"
+		g.printf("w.WriteUint32(math.Float32bits(%s))\n", g.as(expr, t, types.Float32))
+	case types.Float64:
+		g.imports["math"] = "math"
+		g.printf("w.WriteUint64(math.Float64bits(%s))\n", g.as(expr, t, types.Float64))
+	case types.Complex64:
+		g.imports["math"] = "math"
+		g.printf("w.WriteUint32(math.Float32bits(real(%s)))\n", g.as(expr, t, types.Complex64))
+		g.printf("w.WriteUint32(math.Float32bits(imag(%s)))\n", g.as(expr, t, types.Complex64))
+	case types.Complex128:
+		g.imports["math"] = "math"
+		g.printf("w.WriteUint64(math.Float64bits(real(%s)))\n", g.as(expr, t, types.Complex128))
+		g.printf("w.WriteUint64(math.Float64bits(imag(%s)))\n", g.as(expr, t, types.Complex128))
+	default:
+		return fmt.Errorf("unsupported type %s", u)
+	}
+
+	return nil
+}
+
+// ################################################################################################################## \\
+// decoder
+// ################################################################################################################## \\
+
+// decode writes the statements reading into the addressable expr of type t. Like the serializers, it leaves
+// nil pointers, empty slices and empty maps untouched.
+func (g *generator) decode(expr string, t types.Type) error {
+	k, err := g.kindOf(t)
+	if err != nil {
+		return err
+	}
+
+	switch k {
+	case generated:
+		g.printf("if err := %s.UnmarshalBinaryFrom(r); err != nil {\nreturn err\n}\n", receiver(expr))
+		return nil
+	case delegated:
+		g.printf("if err := r.ReadValue(%s); err != nil {\nreturn err\n}\n", address(expr))
+		return nil
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		return g.decodeBasic(expr, t, u)
+	case *types.Pointer:
+		isNil := g.newVar("isNil")
+		g.read(isNil, "r.ReadBool()")
+		g.printf("if !%s {\n%s = new(%s)\n", isNil, expr, g.typeString(u.Elem()))
+		if err = g.decode("*"+operand(expr), u.Elem()); err != nil {
+			return err
+		}
+		g.printf("}\n")
+	case *types.Slice:
+		if isByte(u.Elem()) {
+			v := g.newVar("v")
+			g.read(v, "r.ReadBytes()")
+			g.printf("if len(%s) > 0 {\n%s = %s\n}\n", v, expr, g.convert(v, t, byteSlice))
+			return nil
+		}
+
+		n, i := g.newVar("n"), g.newVar("i")
+		g.read(n, "r.ReadLength(%d)", g.minSize(u.Elem()))
+		g.printf("if %s > 0 {\n%s = make(%s, %s)\n", n, expr, g.typeString(t), n)
+		g.printf("for %s := range %s {\n", i, expr)
+		if err = g.decode(operand(expr)+"["+i+"]", u.Elem()); err != nil {
+			return err
+		}
+		g.printf("}\n}\n")
+	case *types.Array:
+		if isByte(u.Elem()) {
+			v := g.newVar("v")
+			g.read(v, "r.Next(%d)", u.Len())
+			g.printf("copy(%s[:], %s)\n", operand(expr), v)
+			return nil
+		}
+
+		i := g.newVar("i")
+		g.printf("for %s := range %s {\n", i, expr)
+		if err = g.decode(operand(expr)+"["+i+"]", u.Elem()); err != nil {
+			return err
+		}
+		g.printf("}\n")
+	case *types.Map:
+		n, i, k := g.newVar("n"), g.newVar("i"), g.newVar("k")
+		g.read(n, "r.ReadLength(%d)", g.minSize(u.Key(), u.Elem()))
+		g.printf("if %s > 0 {\n%s = make(%s, %s)\n", n, expr, g.typeString(t), n)
+		g.printf("for %s := 0; %s < %s; %s++ {\n", i, i, n, i)
+		g.printf("var %s %s\n", k, g.typeString(u.Key()))
+		if err = g.decode(k, u.Key()); err != nil {
+			return err
+		}
+		v := g.newVar("v")
+		g.printf("var %s %s\n", v, g.typeString(u.Elem()))
+		if err = g.decode(v, u.Elem()); err != nil {
+			return err
+		}
+		g.printf("%s[%s] = %s\n}\n}\n", operand(expr), k, v)
+	}
+
+	return nil
+}
+
+// read writes the statements assigning the result of the Reader call to the new variable v, returning its error.
+func (g *generator) read(v, call string, args ...interface{}) {
+	g.printf("%s, err := "+call+"\nif err != nil {\nreturn err\n}\n", append([]interface{}{v}, args...)...)
+}
+
+// decodeBasic writes the statements reading into expr of type t, whose underlying type is u.
+func (g *generator) decodeBasic(expr string, t types.Type, u *types.Basic) error {
+	v := g.newVar("v")
+	switch u.Kind() {
+	case types.Bool:
+		g.read(v, "r.ReadBool()")
+		g.printf("%s = %s\n", expr, g.convert(v, t, types.Typ[types.Bool]))
+	case types.String:
+		g.read(v, "r.ReadString()")
+		g.printf("%s = %s\n", expr, g.convert(v, t, types.Typ[types.String]))
+	case types.Int, types.Int64, types.Uint, types.Uint64:
+		g.read(v, "r.ReadUint64()")
+		g.printf("%s = %s\n", expr, g.convert(v, t, types.Typ[types.Uint64]))
+	case types.Int32, types.Uint32:
+		g.read(v, "r.ReadUint32()")
+		g.printf("%s = %s\n", expr, g.convert(v, t, types.Typ[types.Uint32]))
+	case types.Int16, types.Uint16:
+		g.read(v, "r.ReadUint16()")
+		g.printf("%s = %s\n", expr, g.convert(v, t, types.Typ[types.Uint16]))
+	case types.Int8, types.Uint8:
+		g.read(v, "r.ReadByte()")
+		g.printf("%s = %s\n", expr, g.convert(v, t, types.Typ[types.Byte]))
+	case types.Float32:
+		g.read(v, "r.ReadUint32()")
+		g.printf("%s = %s\n", expr, g.convert("math.Float32frombits("+v+")", t, types.Typ[types.Float32]))
+	case types.Float64:
+		g.read(v, "r.ReadUint64()")
+		g.printf("%s = %s\n", expr, g.convert("math.Float64frombits("+v+")", t, types.Typ[types.Float64]))
+	case types.Complex64:
+		im := g.newVar("v")
+		g.read(v, "r.ReadUint32()")
+		g.read(im, "r.ReadUint32()")
+		g.printf("%s = %s\n", expr, g.convert(
+			"complex(math.Float32frombits("+v+"), math.Float32frombits("+im+"))", t, types.Typ[types.Complex64]))
+	case types.Complex128:
+		im := g.newVar("v")
+		g.read(v, "r.ReadUint64()")
+		g.read(im, "r.ReadUint64()")
+		g.printf("%s = %s\n", expr, g.convert(
+			"complex(math.Float64frombits("+v+"), math.Float64frombits("+im+"))", t, types.Typ[types.Complex128]))
+	default:
+		return fmt.Errorf("unsupported type %s", u)
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+// Command serializergen generates MarshalBinaryTo and UnmarshalBinaryFrom methods writing and reading structs in the
+// layout of serializer.BinarySerializer without reflection. The binary serializers find the generated methods on
+// their own and call them whenever their options keep the default layout.
+//
+// It is meant to be run by go generate from the package declaring the structs:
+//
+//	//go:generate go run gitlab.com/pietroski-software-company/devex/golang/serializer/cmd/serializergen -type=Order,OrderLine
+//
+// The methods are written to <first type>_binary.go, in lower case, unless -output names another file.
+//
+// Fields of basic types, slices, arrays, maps and pointers of them, and of the structs listed in -type are written
+// inline. Fields of any other type, such as interfaces, time.Time or types with marshaler hooks, are handed over to
+// Writer.WriteValue and Reader.ReadValue, which serialize them reflectively.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("serializergen: ")
+
+	typeNames := flag.String("type", "", "comma-separated list of struct type names; must be set")
+	output := flag.String("output", "", "output file name; default <first type>_binary.go")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: serializergen -type T[,T...] [-output file] [directory]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if args := flag.Args(); len(args) > 0 {
+		dir = args[0]
+	}
+
+	names := strings.Split(*typeNames, ",")
+	outputName := *output
+	if outputName == "" {
+		outputName = strings.ToLower(names[0]) + "_binary.go"
+	}
+
+	if !filepath.IsAbs(outputName) {
+		outputName = filepath.Join(dir, outputName)
+	}
+
+	src, err := generate(dir, names, outputName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err = os.WriteFile(outputName, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,46 @@
+//go:build unit
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Run("generated test models are up to date", func(t *testing.T) {
+		dir := filepath.Join("..", "..", "internal", "gentestmodels")
+		output := filepath.Join(dir, "order_binary.go")
+
+		src, err := generate(dir, []string{"Order", "OrderLine"}, output)
+		require.NoError(t, err)
+
+		want, err := os.ReadFile(output)
+		require.NoError(t, err)
+		assert.Equal(t, string(want), string(src), "run go generate ./internal/gentestmodels")
+	})
+
+	t.Run("invalid types", func(t *testing.T) {
+		dir := filepath.Join("testdata", "invalid")
+
+		for typeName, msg := range map[string]string{
+			"Missing":   "type Missing not found",
+			"NotStruct": "NotStruct is not a struct type",
+			"Generic":   "Generic is generic",
+			"Text":      "Text is serialized through its marshaler methods",
+			"Anonymous": "Anonymous.Point: anonymous struct types are not supported",
+			"Pointer":   "Pointer.Ptr: unsupported type unsafe.Pointer",
+			"Tagged":    `Tagged.N: invalid binary tag ",weight=2": unknown option "weight"`,
+		} {
+			t.Run(typeName, func(t *testing.T) {
+				_, err := generate(dir, []string{typeName}, filepath.Join(dir, "invalid_binary.go"))
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), msg)
+			})
+		}
+	})
+}
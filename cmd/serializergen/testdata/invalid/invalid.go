@@ -0,0 +1,34 @@
+package invalid
+
+import "unsafe"
+
+type (
+	Anonymous struct {
+		Point struct{ X, Y int }
+	}
+
+	Pointer struct {
+		Ptr unsafe.Pointer
+	}
+
+	Tagged struct {
+		N int `binary:",weight=2"`
+	}
+
+	Text struct {
+		S string
+	}
+
+	Generic[T any] struct {
+		V T
+	}
+
+	NotStruct []int
+)
+
+func (t Text) MarshalText() ([]byte, error) { return []byte(t.S), nil }
+
+func (t *Text) UnmarshalText(b []byte) error {
+	t.S = string(b)
+	return nil
+}
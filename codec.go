@@ -0,0 +1,176 @@
+package serializer
+
+import (
+	"reflect"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/binaryx"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/bytesx"
+)
+
+// RegisterCodec makes the binary serializers encode and decode every value of type T through enc and dec
+// instead of walking it reflectively, which is useful for third-party types that implement neither
+// encoding.BinaryMarshaler nor encoding.TextMarshaler.
+//
+// Registered codecs are shared by BinarySerializer, RawBinarySerializer and serializerx.BinarySerializer and take
+// precedence over marshaler hooks. Register codecs before serializing, typically from an init function.
+// RegisterCodec panics when T is a predeclared, pointer or interface type, or when T already has a codec.
+func RegisterCodec[T any](enc func(*Writer, T) error, dec func(*Reader, *T) error) {
+	binaryx.RegisterCodec(reflect.TypeOf((*T)(nil)).Elem(), binaryx.Codec{
+		Encode: func(bbw *bytesx.Writer, value reflect.Value) error {
+			return enc(&Writer{bbw: bbw}, value.Interface().(T))
+		},
+		Decode: func(bbr *bytesx.Reader, value reflect.Value) error {
+			return dec(&Reader{bbr: bbr}, value.Addr().Interface().(*T))
+		},
+	})
+}
+
+// ################################################################################################################## \\
+// codec writer
+// ################################################################################################################## \\
+
+// Writer is handed to the encoding functions registered with RegisterCodec.
+// Fixed width numbers are written in little endian, like the rest of the binary format.
+type Writer struct {
+	bbw *bytesx.Writer
+}
+
+// Write appends p as is. It implements io.Writer and never fails.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.bbw.Write(p)
+	return len(p), nil
+}
+
+// WriteByte appends c. It implements io.ByteWriter and never fails.
+func (w *Writer) WriteByte(c byte) error {
+	w.bbw.Put(c)
+	return nil
+}
+
+func (w *Writer) WriteUint16(v uint16) {
+	w.bbw.Write(bytesx.AddUint16(v))
+}
+
+func (w *Writer) WriteUint32(v uint32) {
+	w.bbw.Write(bytesx.AddUint32(v))
+}
+
+func (w *Writer) WriteUint64(v uint64) {
+	w.bbw.Write(bytesx.AddUint64(v))
+}
+
+// WriteBool appends b as a single byte, 1 for true and 0 for false.
+func (w *Writer) WriteBool(b bool) {
+	if b {
+		w.bbw.Put(1)
+	} else {
+		w.bbw.Put(0)
+	}
+}
+
+// WriteBytes appends bs prefixed by its length.
+func (w *Writer) WriteBytes(bs []byte) {
+	w.bbw.Write(bytesx.AddUint32(uint32(len(bs))))
+	w.bbw.Write(bs)
+}
+
+// WriteString appends str prefixed by its length.
+func (w *Writer) WriteString(str string) {
+	w.bbw.Write(bytesx.AddUint32(uint32(len(str))))
+	w.bbw.Write([]byte(str))
+}
+
+// WriteValue appends the value v points to the way BinarySerializer does with its default options.
+// The methods generated by serializergen use it for the fields they cannot write themselves.
+func (w *Writer) WriteValue(v interface{}) error {
+	value, err := binaryx.Pointee(v)
+	if err != nil {
+		return err
+	}
+
+	generatedLayout.reflectEncode(w.bbw, value)
+	return w.bbw.Err()
+}
+
+// ################################################################################################################## \\
+// codec reader
+// ################################################################################################################## \\
+
+// Reader is handed to the decoding functions registered with RegisterCodec.
+//
+// Reads past the end of the payload return a *models.DecodeError; it is also reported by the serializer
+// even if the decoding function ignores it.
+type Reader struct {
+	bbr *bytesx.Reader
+}
+
+// Next returns the next n bytes. The returned slice aliases the payload and must be copied to be retained.
+func (r *Reader) Next(n int) ([]byte, error) {
+	return r.bbr.Read(n), r.bbr.Err()
+}
+
+// ReadByte implements io.ByteReader.
+func (r *Reader) ReadByte() (byte, error) {
+	return r.bbr.Next(), r.bbr.Err()
+}
+
+// ReadBool reads the bool written by Writer.WriteBool.
+func (r *Reader) ReadBool() (bool, error) {
+	return r.bbr.Next() == 1, r.bbr.Err()
+}
+
+func (r *Reader) ReadUint16() (uint16, error) {
+	return r.bbr.Uint16(), r.bbr.Err()
+}
+
+func (r *Reader) ReadUint32() (uint32, error) {
+	return r.bbr.Uint32(), r.bbr.Err()
+}
+
+func (r *Reader) ReadUint64() (uint64, error) {
+	return r.bbr.Uint64(), r.bbr.Err()
+}
+
+// ReadBytes reads a copy of the bytes written by Writer.WriteBytes.
+func (r *Reader) ReadBytes() ([]byte, error) {
+	bs := r.bbr.Read(int(r.bbr.Uint32()))
+	if err := r.bbr.Err(); err != nil {
+		return nil, err
+	}
+
+	return append([]byte(nil), bs...), nil
+}
+
+// ReadString reads the string written by Writer.WriteString.
+func (r *Reader) ReadString() (string, error) {
+	bs := r.bbr.Read(int(r.bbr.Uint32()))
+	return string(bs), r.bbr.Err()
+}
+
+// ReadLength reads a length written with Writer.WriteUint32, failing when the rest of the payload cannot hold that
+// many elements of at least minSize bytes.
+func (r *Reader) ReadLength(minSize int) (int, error) {
+	n := int(r.bbr.Uint32())
+	if minSize > 0 && !r.bbr.Ensure(n*minSize) {
+		return 0, r.bbr.Err()
+	}
+
+	return n, r.bbr.Err()
+}
+
+// ReadValue reads into the value v points to, the way BinarySerializer does with its default options.
+// The methods generated by serializergen use it for the fields they cannot read themselves.
+func (r *Reader) ReadValue(v interface{}) error {
+	value, err := binaryx.Pointee(v)
+	if err != nil {
+		return err
+	}
+
+	generatedLayout.reflectDecode(r.bbr, value)
+	return r.bbr.Err()
+}
+
+// Len returns the number of bytes left in the payload.
+func (r *Reader) Len() int {
+	return r.bbr.Len()
+}
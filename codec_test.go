@@ -0,0 +1,148 @@
+//go:build unit
+
+package serializer
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/testmodels"
+)
+
+func init() {
+	RegisterCodec(encodeCoordinates, decodeCoordinates)
+}
+
+func encodeCoordinates(w *Writer, c testmodels.Coordinates) error {
+	if math.Abs(c.Lat) > 90 || math.Abs(c.Lng) > 180 {
+		return testmodels.ErrInvalidCoordinates
+	}
+
+	w.WriteUint64(math.Float64bits(c.Lat))
+	w.WriteUint64(math.Float64bits(c.Lng))
+	return nil
+}
+
+func decodeCoordinates(r *Reader, c *testmodels.Coordinates) error {
+	lat, err := r.ReadUint64()
+	if err != nil {
+		return err
+	}
+
+	lng, err := r.ReadUint64()
+	if err != nil {
+		return err
+	}
+
+	c.Lat, c.Lng = math.Float64frombits(lat), math.Float64frombits(lng)
+	if math.Abs(c.Lat) > 90 || math.Abs(c.Lng) > 180 {
+		return testmodels.ErrInvalidCoordinates
+	}
+
+	return nil
+}
+
+type (
+	// smallCount gets its codec once a plan was compiled for it, see TestRegisterCodec.
+	smallCount struct {
+		N uint64
+	}
+
+	smallCountHolder struct {
+		Counts []smallCount
+	}
+
+	// smallCents gets its codec once its slices were written through the primitive slice fast path.
+	smallCents int64
+
+	smallCentsHolder struct {
+		Cents []smallCents
+	}
+)
+
+var registerSmallCount, registerSmallCents sync.Once
+
+func TestRegisterCodec(t *testing.T) {
+	t.Run("duplicate codec", func(t *testing.T) {
+		assert.Panics(t, func() { RegisterCodec(encodeCoordinates, decodeCoordinates) })
+	})
+
+	t.Run("predeclared type", func(t *testing.T) {
+		assert.Panics(t, func() {
+			RegisterCodec(
+				func(w *Writer, v int) error { return nil },
+				func(r *Reader, v *int) error { return nil },
+			)
+		})
+	})
+
+	t.Run("pointer type", func(t *testing.T) {
+		assert.Panics(t, func() {
+			RegisterCodec(
+				func(w *Writer, v *testmodels.Money) error { return nil },
+				func(r *Reader, v **testmodels.Money) error { return nil },
+			)
+		})
+	})
+	t.Run("codec registered after serializing", func(t *testing.T) {
+		s := NewBinarySerializer()
+		msg := smallCountHolder{Counts: []smallCount{{N: 1}, {N: 2}, {N: 3}}}
+
+		_, err := s.Serialize(&msg)
+		require.NoError(t, err)
+
+		registerSmallCount.Do(func() {
+			RegisterCodec(
+				func(w *Writer, c smallCount) error { return w.WriteByte(byte(c.N)) },
+				func(r *Reader, c *smallCount) error {
+					n, err := r.ReadByte()
+					c.N = uint64(n)
+					return err
+				},
+			)
+		})
+
+		bs, err := s.Serialize(&msg)
+		require.NoError(t, err)
+		// the length of the slice followed by a byte per element
+		assert.Len(t, bs, 4+len(msg.Counts))
+
+		var target smallCountHolder
+		err = s.Deserialize(bs, &target)
+		require.NoError(t, err)
+		assert.Equal(t, msg, target)
+	})
+
+	t.Run("codec registered after the primitive slice fast path", func(t *testing.T) {
+		s := NewBinarySerializer()
+		msg := smallCentsHolder{Cents: []smallCents{1, 2, 3}}
+
+		bs, err := s.Serialize(&msg)
+		require.NoError(t, err)
+		assert.Len(t, bs, 4+8*len(msg.Cents))
+
+		registerSmallCents.Do(func() {
+			RegisterCodec(
+				func(w *Writer, c smallCents) error { return w.WriteByte(byte(c)) },
+				func(r *Reader, c *smallCents) error {
+					n, err := r.ReadByte()
+					*c = smallCents(n)
+					return err
+				},
+			)
+		})
+
+		bs, err = s.Serialize(&msg)
+		require.NoError(t, err)
+		assert.Len(t, bs, 4+len(msg.Cents))
+
+		var target smallCentsHolder
+		err = s.Deserialize(bs, &target)
+		require.NoError(t, err)
+		assert.Equal(t, msg, target)
+	})
+}
@@ -0,0 +1,275 @@
+package serializer
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"sync"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/compressx"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
+)
+
+// CompressionAlgorithm identifies a compression codec. Its value is the one-byte marker written in front of every
+// payload by CompressionSerializer, so existing values must never change.
+type CompressionAlgorithm uint8
+
+const (
+	// Uncompressed marks payloads stored as they are, because they were below the size threshold or did not shrink.
+	Uncompressed CompressionAlgorithm = iota
+	// Gzip compresses with compress/gzip.
+	Gzip
+	// Flate compresses with compress/flate.
+	Flate
+	// Zlib compresses with compress/zlib.
+	Zlib
+	// LZ4 compresses into LZ4 blocks with a pure Go codec, trading compression ratio for speed.
+	LZ4
+)
+
+func (a CompressionAlgorithm) String() string {
+	switch a {
+	case Uncompressed:
+		return "uncompressed"
+	case Gzip:
+		return "gzip"
+	case Flate:
+		return "flate"
+	case Zlib:
+		return "zlib"
+	case LZ4:
+		return "lz4"
+	default:
+		return fmt.Sprintf("algorithm %d", uint8(a))
+	}
+}
+
+// DefaultCompressionThreshold is the payload size below which CompressionSerializer stores payloads uncompressed.
+const DefaultCompressionThreshold = 256
+
+// DefaultMaxDecompressedSize is the size past which CompressionSerializer refuses to decompress payloads.
+const DefaultMaxDecompressedSize = 64 << 20
+
+type compressionOptions struct {
+	threshold       int
+	level           int
+	maxDecompressed int
+}
+
+// CompressionOption configures CompressionSerializer.
+type CompressionOption func(*compressionOptions)
+
+// WithCompressionThreshold sets the payload size, in bytes, below which payloads are stored uncompressed.
+func WithCompressionThreshold(threshold int) CompressionOption {
+	return func(o *compressionOptions) {
+		o.threshold = threshold
+	}
+}
+
+// WithCompressionLevel sets the level of the Gzip, Flate and Zlib algorithms, as defined by compress/flate.
+// It is ignored by LZ4.
+func WithCompressionLevel(level int) CompressionOption {
+	return func(o *compressionOptions) {
+		o.level = level
+	}
+}
+
+// WithMaxDecompressedSize sets the size, in bytes, past which payloads fail to decompress with
+// models.ErrDecompressedTooLarge, which guards Deserialize against decompression bombs.
+func WithMaxDecompressedSize(size int) CompressionOption {
+	return func(o *compressionOptions) {
+		o.maxDecompressed = size
+	}
+}
+
+// CompressionSerializer decorates a models.Serializer, compressing the payloads it serializes and decompressing
+// them before they are deserialized. Every payload starts with the marker of its algorithm, so a
+// CompressionSerializer reads the payloads of any algorithm whatever the one it writes.
+type CompressionSerializer struct {
+	inner models.Serializer
+	algo  CompressionAlgorithm
+	opts  compressionOptions
+
+	writers sync.Pool
+}
+
+// WithCompression wraps inner into a CompressionSerializer writing payloads compressed with algo.
+// It panics when algo is unknown or the compression level is invalid for it.
+func WithCompression(
+	inner models.Serializer, algo CompressionAlgorithm, opts ...CompressionOption,
+) *CompressionSerializer {
+	s := &CompressionSerializer{
+		inner: inner,
+		algo:  algo,
+		opts: compressionOptions{
+			threshold:       DefaultCompressionThreshold,
+			level:           flate.DefaultCompression,
+			maxDecompressed: DefaultMaxDecompressedSize,
+		},
+	}
+	for _, opt := range opts {
+		opt(&s.opts)
+	}
+
+	switch algo {
+	case Gzip, Flate, Zlib:
+		// the stdlib writers are costly to allocate, so they are reset and reused
+		if _, err := s.newWriter(io.Discard); err != nil {
+			panic(fmt.Sprintf("serializer: %s: %v", algo, err))
+		}
+
+		s.writers.New = func() interface{} {
+			w, _ := s.newWriter(io.Discard)
+			return w
+		}
+	case LZ4:
+	default:
+		panic(fmt.Sprintf("serializer: unknown compression %s", algo))
+	}
+
+	return s
+}
+
+func (s *CompressionSerializer) Serialize(payload interface{}) ([]byte, error) {
+	bs, err := s.inner.Serialize(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	compressed, err := s.compress(bs)
+	if err != nil {
+		return nil, fmt.Errorf(models.EncodeErrMsg, err)
+	}
+
+	return compressed, nil
+}
+
+func (s *CompressionSerializer) Deserialize(payload []byte, target interface{}) error {
+	bs, err := s.decompress(payload)
+	if err != nil {
+		return fmt.Errorf(models.DecodeErrMsg, err)
+	}
+
+	return s.inner.Deserialize(bs, target)
+}
+
+// DataRebind hands over to the inner serializer, as the payload never leaves the process.
+func (s *CompressionSerializer) DataRebind(payload interface{}, target interface{}) error {
+	return s.inner.DataRebind(payload, target)
+}
+
+// ################################################################################################################## \\
+// codecs
+// ################################################################################################################## \\
+
+type resetWriter interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+}
+
+func (s *CompressionSerializer) newWriter(w io.Writer) (resetWriter, error) {
+	switch s.algo {
+	case Gzip:
+		return gzip.NewWriterLevel(w, s.opts.level)
+	case Flate:
+		return flate.NewWriter(w, s.opts.level)
+	default:
+		return zlib.NewWriterLevel(w, s.opts.level)
+	}
+}
+
+func (s *CompressionSerializer) compress(bs []byte) ([]byte, error) {
+	if len(bs) < s.opts.threshold {
+		return store(bs), nil
+	}
+
+	if s.algo == LZ4 {
+		compressed := compressx.AppendLZ4([]byte{byte(LZ4)}, bs)
+		if len(compressed) >= len(bs)+1 {
+			return store(bs), nil
+		}
+
+		return compressed, nil
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, len(bs)/2+1))
+	buf.WriteByte(byte(s.algo))
+
+	w := s.writers.Get().(resetWriter)
+	defer s.writers.Put(w)
+
+	w.Reset(buf)
+	if _, err := w.Write(bs); err != nil {
+		return nil, &models.CompressionError{Algorithm: s.algo.String(), Err: err}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, &models.CompressionError{Algorithm: s.algo.String(), Err: err}
+	}
+
+	if buf.Len() >= len(bs)+1 {
+		return store(bs), nil
+	}
+
+	return buf.Bytes(), nil
+}
+
+func store(bs []byte) []byte {
+	stored := make([]byte, len(bs)+1)
+	stored[0] = byte(Uncompressed)
+	copy(stored[1:], bs)
+	return stored
+}
+
+func (s *CompressionSerializer) decompress(payload []byte) ([]byte, error) {
+	if len(payload) == 0 {
+		return nil, &models.CompressionError{Err: models.ErrUnknownCompression}
+	}
+
+	algo, data := CompressionAlgorithm(payload[0]), payload[1:]
+
+	var (
+		r   io.ReadCloser
+		err error
+	)
+	switch algo {
+	case Uncompressed:
+		return data, nil
+	case LZ4:
+		bs, err := compressx.DecodeLZ4(data, s.opts.maxDecompressed)
+		if err != nil {
+			return nil, &models.CompressionError{Algorithm: algo.String(), Err: err}
+		}
+
+		return bs, nil
+	case Gzip:
+		r, err = gzip.NewReader(bytes.NewReader(data))
+	case Flate:
+		r = flate.NewReader(bytes.NewReader(data))
+	case Zlib:
+		r, err = zlib.NewReader(bytes.NewReader(data))
+	default:
+		return nil, &models.CompressionError{Algorithm: algo.String(), Err: models.ErrUnknownCompression}
+	}
+
+	if err != nil {
+		return nil, &models.CompressionError{Algorithm: algo.String(), Err: err}
+	}
+
+	defer r.Close()
+
+	// one byte past the limit tells payloads of exactly the maximum size from larger ones
+	bs, err := io.ReadAll(io.LimitReader(r, int64(s.opts.maxDecompressed)+1))
+	if err != nil {
+		return nil, &models.CompressionError{Algorithm: algo.String(), Err: err}
+	}
+
+	if len(bs) > s.opts.maxDecompressed {
+		return nil, &models.CompressionError{Algorithm: algo.String(), Err: models.ErrDecompressedTooLarge}
+	}
+
+	return bs, nil
+}
@@ -0,0 +1,272 @@
+//go:build unit
+
+package serializer
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/fakes"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/compressx"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/testmodels"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
+)
+
+func compressionTestItems(n int) []testmodels.Item {
+	items := make([]testmodels.Item, n)
+	for i := range items {
+		items[i] = testmodels.Item{
+			Id:     "item-" + strings.Repeat("x", 16),
+			ItemId: uint64(i),
+			Number: int64(i % 4),
+			SubItem: &testmodels.SubItem{
+				Date:   1_700_000_000,
+				Amount: 42,
+			},
+		}
+	}
+
+	return items
+}
+
+func TestCompressionSerializer(t *testing.T) {
+	algos := []CompressionAlgorithm{Gzip, Flate, Zlib, LZ4}
+	inners := map[string]models.Serializer{
+		"binary": NewBinarySerializer(),
+		"json":   NewJsonSerializer(),
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		items := compressionTestItems(64)
+		for name, inner := range inners {
+			for _, algo := range algos {
+				t.Run(name+"/"+algo.String(), func(t *testing.T) {
+					s := WithCompression(inner, algo)
+
+					raw, err := inner.Serialize(items)
+					require.NoError(t, err)
+
+					bs, err := s.Serialize(items)
+					require.NoError(t, err)
+					assert.Equal(t, byte(algo), bs[0])
+					assert.Less(t, len(bs), len(raw))
+
+					var target []testmodels.Item
+					require.NoError(t, s.Deserialize(bs, &target))
+					assert.Equal(t, items, target)
+				})
+			}
+		}
+	})
+
+	t.Run("below threshold", func(t *testing.T) {
+		for _, algo := range algos {
+			s := WithCompression(NewBinarySerializer(), algo, WithCompressionThreshold(1<<20))
+
+			items := compressionTestItems(64)
+			bs, err := s.Serialize(items)
+			require.NoError(t, err)
+			assert.Equal(t, byte(Uncompressed), bs[0])
+
+			var target []testmodels.Item
+			require.NoError(t, s.Deserialize(bs, &target))
+			assert.Equal(t, items, target)
+		}
+	})
+
+	t.Run("incompressible", func(t *testing.T) {
+		data := make([]byte, 4096)
+		rand.New(rand.NewSource(1)).Read(data)
+
+		for _, algo := range algos {
+			s := WithCompression(NewBinarySerializer(), algo, WithCompressionThreshold(0))
+
+			bs, err := s.Serialize(data)
+			require.NoError(t, err)
+			assert.Equal(t, byte(Uncompressed), bs[0])
+
+			var target []byte
+			require.NoError(t, s.Deserialize(bs, &target))
+			assert.Equal(t, data, target)
+		}
+	})
+
+	t.Run("any algorithm is read", func(t *testing.T) {
+		items := compressionTestItems(64)
+		for _, writer := range algos {
+			bs, err := WithCompression(NewBinarySerializer(), writer).Serialize(items)
+			require.NoError(t, err)
+
+			for _, reader := range algos {
+				var target []testmodels.Item
+				require.NoError(t, WithCompression(NewBinarySerializer(), reader).Deserialize(bs, &target))
+				assert.Equal(t, items, target)
+			}
+		}
+	})
+
+	t.Run("compression level", func(t *testing.T) {
+		items := compressionTestItems(64)
+		s := WithCompression(NewBinarySerializer(), Gzip, WithCompressionLevel(flate.BestSpeed))
+
+		bs, err := s.Serialize(items)
+		require.NoError(t, err)
+
+		var target []testmodels.Item
+		require.NoError(t, s.Deserialize(bs, &target))
+		assert.Equal(t, items, target)
+	})
+
+	t.Run("unknown algorithm", func(t *testing.T) {
+		s := WithCompression(NewBinarySerializer(), LZ4)
+
+		var target []testmodels.Item
+		err := s.Deserialize([]byte{0xff, 1, 2, 3}, &target)
+		assert.ErrorIs(t, err, models.ErrUnknownCompression)
+
+		var compressionErr *models.CompressionError
+		assert.ErrorAs(t, err, &compressionErr)
+
+		assert.ErrorIs(t, s.Deserialize(nil, &target), models.ErrUnknownCompression)
+	})
+
+	t.Run("corrupted payloads", func(t *testing.T) {
+		items := compressionTestItems(64)
+		for _, algo := range algos {
+			s := WithCompression(NewBinarySerializer(), algo)
+
+			bs, err := s.Serialize(items)
+			require.NoError(t, err)
+
+			for _, cut := range []int{1, 2, len(bs) / 2, len(bs) - 1} {
+				var target []testmodels.Item
+				assert.Error(t, s.Deserialize(bs[:cut], &target), "%s cut at %d", algo, cut)
+			}
+
+			corrupted := bytes.Clone(bs)
+			for i := 1; i < len(corrupted); i += 7 {
+				corrupted[i] ^= 0x5a
+			}
+
+			var target []testmodels.Item
+			assert.NotPanics(t, func() {
+				_ = s.Deserialize(corrupted, &target)
+			})
+		}
+	})
+
+	t.Run("max decompressed size", func(t *testing.T) {
+		bomb := make([]byte, 1<<20)
+		inner, err := NewRawBinarySerializer().Serialize(bomb)
+		require.NoError(t, err)
+
+		for _, algo := range algos {
+			bs, err := WithCompression(NewRawBinarySerializer(), algo).Serialize(bomb)
+			require.NoError(t, err)
+			require.Less(t, len(bs), len(bomb)/100, algo)
+
+			var target []byte
+			err = WithCompression(NewRawBinarySerializer(), algo, WithMaxDecompressedSize(1<<16)).Deserialize(bs, &target)
+			assert.ErrorIs(t, err, models.ErrDecompressedTooLarge, algo)
+
+			var compressionErr *models.CompressionError
+			assert.ErrorAs(t, err, &compressionErr, algo)
+
+			s := WithCompression(NewRawBinarySerializer(), algo, WithMaxDecompressedSize(len(inner)))
+			require.NoError(t, s.Deserialize(bs, &target), algo)
+			assert.Equal(t, bomb, target)
+		}
+	})
+
+	t.Run("inner errors", func(t *testing.T) {
+		s := WithCompression(NewBinarySerializer(), Gzip)
+
+		_, err := s.Serialize(make(chan int))
+		assert.Error(t, err)
+	})
+
+	t.Run("data rebind", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		inner := fakes.NewMockSerializer(ctrl)
+
+		payload, target := testmodels.Item{Id: "id"}, &testmodels.Item{}
+		inner.EXPECT().DataRebind(payload, target).Return(nil)
+
+		assert.NoError(t, WithCompression(inner, Gzip).DataRebind(payload, target))
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		assert.Panics(t, func() {
+			WithCompression(NewBinarySerializer(), CompressionAlgorithm(42))
+		})
+
+		assert.Panics(t, func() {
+			WithCompression(NewBinarySerializer(), Zlib, WithCompressionLevel(42))
+		})
+	})
+}
+
+func TestLZ4(t *testing.T) {
+	random := make([]byte, 1<<16+100)
+	rand.New(rand.NewSource(1)).Read(random)
+
+	inputs := map[string][]byte{
+		"empty":         {},
+		"one byte":      {1},
+		"mf limit":      bytes.Repeat([]byte{7}, 12),
+		"past mf limit": bytes.Repeat([]byte{7}, 13),
+		"repetitive":    bytes.Repeat([]byte("serializer"), 1000),
+		"long run":      bytes.Repeat([]byte{0}, 70_000),
+		"random":        random,
+		"mixed":         append(bytes.Repeat([]byte("ab"), 300), random[:5000]...),
+	}
+
+	for name, input := range inputs {
+		t.Run(name, func(t *testing.T) {
+			compressed := compressx.AppendLZ4(nil, input)
+
+			decompressed, err := compressx.DecodeLZ4(compressed, len(input))
+			require.NoError(t, err)
+			assert.Equal(t, len(input), len(decompressed))
+			assert.True(t, bytes.Equal(input, decompressed))
+
+			for cut := range min(len(compressed), 64) {
+				assert.NotPanics(t, func() {
+					_, _ = compressx.DecodeLZ4(compressed[:cut], len(input))
+				})
+			}
+		})
+	}
+
+	t.Run("corrupted", func(t *testing.T) {
+		// announces a size far beyond what the block can expand to
+		_, err := compressx.DecodeLZ4([]byte{0xff, 0xff, 0xff, 0x7f, 0x10, 1}, math.MaxInt)
+		assert.ErrorIs(t, err, compressx.ErrCorrupt)
+
+		// a match pointing before the start of the block
+		_, err = compressx.DecodeLZ4([]byte{8, 0x10, 1, 9, 0}, math.MaxInt)
+		assert.ErrorIs(t, err, compressx.ErrCorrupt)
+	})
+
+	t.Run("forged size", func(t *testing.T) {
+		// a block of 1 MiB may announce up to 255 MiB, past the default maximum decompressed size
+		payload := binary.AppendUvarint([]byte{byte(LZ4)}, 255<<20)
+		payload = append(payload, make([]byte, 1<<20)...)
+
+		var target []byte
+		err := WithCompression(NewRawBinarySerializer(), LZ4).Deserialize(payload, &target)
+		assert.ErrorIs(t, err, models.ErrDecompressedTooLarge)
+
+		_, err = compressx.DecodeLZ4(payload[1:], 1<<20)
+		assert.ErrorIs(t, err, models.ErrDecompressedTooLarge)
+	})
+}
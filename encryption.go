@@ -0,0 +1,170 @@
+package serializer
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
+)
+
+// KeyProvider hands the keys used by EncryptionSerializer and SigningSerializer. Keys are rotated by changing the current key while keeping
+// the previous ones available to Key, as long as payloads sealed or signed with them are around.
+type KeyProvider interface {
+	// CurrentKey returns the key payloads are sealed or signed with, along with the id it is known by.
+	CurrentKey() (keyID string, key []byte, err error)
+	// Key returns the key known by keyID.
+	Key(keyID string) ([]byte, error)
+}
+
+// KeyRing is a KeyProvider holding its keys in memory.
+type KeyRing struct {
+	// Current is the id of the key payloads are sealed or signed with.
+	Current string
+	// Keys maps the key ids to their keys; EncryptionSerializer requires 16, 24 or 32 byte AES keys.
+	Keys map[string][]byte
+}
+
+func (kr KeyRing) CurrentKey() (string, []byte, error) {
+	key, err := kr.Key(kr.Current)
+	return kr.Current, key, err
+}
+
+func (kr KeyRing) Key(keyID string) ([]byte, error) {
+	key, ok := kr.Keys[keyID]
+	if !ok {
+		return nil, models.ErrUnknownKey
+	}
+
+	return key, nil
+}
+
+// maxKeyIDLen is the length limit of key ids, written with a single byte.
+const maxKeyIDLen = 255
+
+// aesGCM marks payloads sealed with AES-GCM, leaving room for other AEADs.
+const aesGCM byte = 1
+
+// EncryptionSerializer decorates a models.Serializer, sealing the payloads it serializes with AES-GCM and opening
+// them before they are deserialized. Payloads are laid out as the cipher marker, the key id prefixed with its length,
+// a random nonce and the sealed payload; the header is authenticated along with the payload.
+type EncryptionSerializer struct {
+	inner models.Serializer
+	keys  KeyProvider
+}
+
+// WithEncryption wraps inner into an EncryptionSerializer sealing payloads with the keys of keys.
+func WithEncryption(inner models.Serializer, keys KeyProvider) *EncryptionSerializer {
+	return &EncryptionSerializer{
+		inner: inner,
+		keys:  keys,
+	}
+}
+
+func (s *EncryptionSerializer) Serialize(payload interface{}) ([]byte, error) {
+	bs, err := s.inner.Serialize(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := s.seal(bs)
+	if err != nil {
+		return nil, fmt.Errorf(models.EncodeErrMsg, err)
+	}
+
+	return sealed, nil
+}
+
+func (s *EncryptionSerializer) Deserialize(payload []byte, target interface{}) error {
+	bs, err := s.open(payload)
+	if err != nil {
+		return fmt.Errorf(models.DecodeErrMsg, err)
+	}
+
+	return s.inner.Deserialize(bs, target)
+}
+
+// DataRebind hands over to the inner serializer, as the payload never leaves the process.
+func (s *EncryptionSerializer) DataRebind(payload interface{}, target interface{}) error {
+	return s.inner.DataRebind(payload, target)
+}
+
+// ################################################################################################################## \\
+// sealing
+// ################################################################################################################## \\
+
+func (s *EncryptionSerializer) seal(bs []byte) ([]byte, error) {
+	keyID, key, err := s.keys.CurrentKey()
+	if err != nil {
+		return nil, &models.EncryptionError{KeyID: keyID, Err: err}
+	}
+
+	if len(keyID) > maxKeyIDLen {
+		return nil, &models.EncryptionError{
+			KeyID: keyID,
+			Err:   fmt.Errorf("key id longer than %d bytes", maxKeyIDLen),
+		}
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, &models.EncryptionError{KeyID: keyID, Err: err}
+	}
+
+	headerLen := 2 + len(keyID)
+	sealed := make([]byte, headerLen+aead.NonceSize(), headerLen+aead.NonceSize()+len(bs)+aead.Overhead())
+	sealed[0] = aesGCM
+	sealed[1] = byte(len(keyID))
+	copy(sealed[2:], keyID)
+
+	nonce := sealed[headerLen:]
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, &models.EncryptionError{KeyID: keyID, Err: err}
+	}
+
+	return aead.Seal(sealed, nonce, bs, sealed[:headerLen]), nil
+}
+
+func (s *EncryptionSerializer) open(payload []byte) ([]byte, error) {
+	if len(payload) < 2 || payload[0] != aesGCM || len(payload) < 2+int(payload[1]) {
+		return nil, &models.EncryptionError{Err: errMalformedSealed}
+	}
+
+	headerLen := 2 + int(payload[1])
+	keyID := string(payload[2:headerLen])
+
+	key, err := s.keys.Key(keyID)
+	if err != nil {
+		return nil, &models.EncryptionError{KeyID: keyID, Err: err}
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, &models.EncryptionError{KeyID: keyID, Err: err}
+	}
+
+	if len(payload) < headerLen+aead.NonceSize()+aead.Overhead() {
+		return nil, &models.EncryptionError{KeyID: keyID, Err: errMalformedSealed}
+	}
+
+	nonce, sealed := payload[headerLen:headerLen+aead.NonceSize()], payload[headerLen+aead.NonceSize():]
+	bs, err := aead.Open(nil, nonce, sealed, payload[:headerLen])
+	if err != nil {
+		return nil, &models.EncryptionError{KeyID: keyID, Err: models.ErrAuthenticationFailed}
+	}
+
+	return bs, nil
+}
+
+var errMalformedSealed = errors.New("malformed sealed payload")
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
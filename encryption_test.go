@@ -0,0 +1,158 @@
+//go:build unit
+
+package serializer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/fakes"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/testmodels"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
+)
+
+func TestEncryptionSerializer(t *testing.T) {
+	keys := KeyRing{
+		Current: "2024-06",
+		Keys: map[string][]byte{
+			"2024-01": bytes.Repeat([]byte{1}, 16),
+			"2024-06": bytes.Repeat([]byte{2}, 32),
+		},
+	}
+	item := testmodels.Item{
+		Id:      "session",
+		ItemId:  7,
+		Number:  -42,
+		SubItem: &testmodels.SubItem{Date: 1_700_000_000, Amount: 10},
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		for name, inner := range map[string]models.Serializer{
+			"binary": NewBinarySerializer(),
+			"json":   NewJsonSerializer(),
+		} {
+			t.Run(name, func(t *testing.T) {
+				s := WithEncryption(inner, keys)
+
+				raw, err := inner.Serialize(item)
+				require.NoError(t, err)
+
+				bs, err := s.Serialize(item)
+				require.NoError(t, err)
+				assert.NotContains(t, string(bs), string(raw))
+				assert.Contains(t, string(bs), "2024-06")
+
+				var target testmodels.Item
+				require.NoError(t, s.Deserialize(bs, &target))
+				assert.Equal(t, item, target)
+			})
+		}
+	})
+
+	t.Run("random nonces", func(t *testing.T) {
+		s := WithEncryption(NewBinarySerializer(), keys)
+
+		first, err := s.Serialize(item)
+		require.NoError(t, err)
+
+		second, err := s.Serialize(item)
+		require.NoError(t, err)
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("key rotation", func(t *testing.T) {
+		old := keys
+		old.Current = "2024-01"
+
+		bs, err := WithEncryption(NewBinarySerializer(), old).Serialize(item)
+		require.NoError(t, err)
+
+		var target testmodels.Item
+		require.NoError(t, WithEncryption(NewBinarySerializer(), keys).Deserialize(bs, &target))
+		assert.Equal(t, item, target)
+
+		retired := KeyRing{Current: "2024-06", Keys: map[string][]byte{"2024-06": keys.Keys["2024-06"]}}
+		err = WithEncryption(NewBinarySerializer(), retired).Deserialize(bs, &target)
+		assert.ErrorIs(t, err, models.ErrUnknownKey)
+
+		var encryptionErr *models.EncryptionError
+		require.ErrorAs(t, err, &encryptionErr)
+		assert.Equal(t, "2024-01", encryptionErr.KeyID)
+	})
+
+	t.Run("authentication failure", func(t *testing.T) {
+		s := WithEncryption(NewBinarySerializer(), keys)
+
+		bs, err := s.Serialize(item)
+		require.NoError(t, err)
+
+		for i := 2; i < len(bs); i++ {
+			tampered := bytes.Clone(bs)
+			tampered[i] ^= 1
+
+			var target testmodels.Item
+			err = s.Deserialize(tampered, &target)
+
+			var encryptionErr *models.EncryptionError
+			assert.ErrorAs(t, err, &encryptionErr, "byte %d", i)
+		}
+
+		// same id, different key
+		forged := KeyRing{Current: "2024-06", Keys: map[string][]byte{"2024-06": bytes.Repeat([]byte{3}, 32)}}
+
+		var target testmodels.Item
+		err = WithEncryption(NewBinarySerializer(), forged).Deserialize(bs, &target)
+		assert.ErrorIs(t, err, models.ErrAuthenticationFailed)
+	})
+
+	t.Run("malformed payloads", func(t *testing.T) {
+		s := WithEncryption(NewBinarySerializer(), keys)
+
+		bs, err := s.Serialize(item)
+		require.NoError(t, err)
+
+		for cut := 0; cut < len(bs); cut++ {
+			var target testmodels.Item
+			assert.Error(t, s.Deserialize(bs[:cut], &target), "cut at %d", cut)
+		}
+
+		var target testmodels.Item
+		assert.Error(t, s.Deserialize([]byte{0x42, 0, 1, 2, 3}, &target))
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		_, err := WithEncryption(NewBinarySerializer(), KeyRing{Current: "missing"}).Serialize(item)
+		assert.ErrorIs(t, err, models.ErrUnknownKey)
+
+		short := KeyRing{Current: "short", Keys: map[string][]byte{"short": {1, 2, 3}}}
+		_, err = WithEncryption(NewBinarySerializer(), short).Serialize(item)
+		var encryptionErr *models.EncryptionError
+		assert.ErrorAs(t, err, &encryptionErr)
+	})
+
+	t.Run("composes with compression", func(t *testing.T) {
+		s := WithEncryption(WithCompression(NewBinarySerializer(), LZ4), keys)
+
+		items := compressionTestItems(32)
+		bs, err := s.Serialize(items)
+		require.NoError(t, err)
+
+		var target []testmodels.Item
+		require.NoError(t, s.Deserialize(bs, &target))
+		assert.Equal(t, items, target)
+	})
+
+	t.Run("data rebind", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		inner := fakes.NewMockSerializer(ctrl)
+
+		payload, target := testmodels.Item{Id: "id"}, &testmodels.Item{}
+		inner.EXPECT().DataRebind(payload, target).Return(nil)
+
+		assert.NoError(t, WithEncryption(inner, keys).DataRebind(payload, target))
+	})
+}
@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: gitlab.com/pietroski-software-company/devex/golang/serializer/models (interfaces: Codec)
+//
+// Generated by this command:
+//
+//	mockgen -package fakes -destination ../fakes/fake_codec.go . Codec
+//
+
+// Package fakes is a generated GoMock package.
+package fakes
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCodec is a mock of Codec interface.
+type MockCodec[T any] struct {
+	ctrl     *gomock.Controller
+	recorder *MockCodecMockRecorder[T]
+	isgomock struct{}
+}
+
+// MockCodecMockRecorder is the mock recorder for MockCodec.
+type MockCodecMockRecorder[T any] struct {
+	mock *MockCodec[T]
+}
+
+// NewMockCodec creates a new mock instance.
+func NewMockCodec[T any](ctrl *gomock.Controller) *MockCodec[T] {
+	mock := &MockCodec[T]{ctrl: ctrl}
+	mock.recorder = &MockCodecMockRecorder[T]{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCodec[T]) EXPECT() *MockCodecMockRecorder[T] {
+	return m.recorder
+}
+
+// Decode mocks base method.
+func (m *MockCodec[T]) Decode(payload []byte) (T, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decode", payload)
+	ret0, _ := ret[0].(T)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decode indicates an expected call of Decode.
+func (mr *MockCodecMockRecorder[T]) Decode(payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decode", reflect.TypeOf((*MockCodec[T])(nil).Decode), payload)
+}
+
+// Encode mocks base method.
+func (m *MockCodec[T]) Encode(v T) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Encode", v)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Encode indicates an expected call of Encode.
+func (mr *MockCodecMockRecorder[T]) Encode(v any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Encode", reflect.TypeOf((*MockCodec[T])(nil).Encode), v)
+}
@@ -0,0 +1,41 @@
+//go:build unit
+
+package serializer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/testmodels"
+)
+
+func TestSchemaFingerprint(t *testing.T) {
+	t.Run("stable", func(t *testing.T) {
+		// FNV-1a of `struct{SKU=1 string;Quantity=2 int32;}`
+		assert.Equal(t, uint64(0x100dedc51f187f9c), SchemaFingerprint(testmodels.EventLine{}))
+		assert.Equal(t, SchemaFingerprint(testmodels.EventLine{}), SchemaFingerprint(&testmodels.EventLine{}))
+	})
+
+	t.Run("renamed type", func(t *testing.T) {
+		assert.Equal(t, SchemaFingerprint(testmodels.Item{}), SchemaFingerprint(testmodels.RenamedItem{}))
+	})
+
+	t.Run("changed layout", func(t *testing.T) {
+		assert.NotEqual(t, SchemaFingerprint(testmodels.Item{}), SchemaFingerprint(testmodels.ReorderedItem{}))
+		assert.NotEqual(t, SchemaFingerprint(testmodels.EventV1{}), SchemaFingerprint(testmodels.EventV2{}))
+		assert.NotEqual(t, SchemaFingerprint(testmodels.EventV1{}), SchemaFingerprint(testmodels.EventKindChanged{}))
+		assert.NotEqual(t, SchemaFingerprint([]int32{}), SchemaFingerprint([]int64{}))
+	})
+
+	t.Run("recursive type", func(t *testing.T) {
+		assert.NotZero(t, SchemaFingerprint(testmodels.TreeNode{}))
+	})
+
+	t.Run("unexported fields policy", func(t *testing.T) {
+		assert.NotEqual(t,
+			SchemaFingerprint(testmodels.UnexportedFieldsTestData{}),
+			SchemaFingerprint(testmodels.UnexportedFieldsTestData{}, WithUnexportedFields(IncludeUnexportedFields)),
+		)
+	})
+}
@@ -0,0 +1,60 @@
+package serializer
+
+import (
+	"reflect"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/binaryx"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/bytesx"
+)
+
+// GeneratedMarshaler is implemented by the structs serializergen generated methods for.
+// MarshalBinaryTo writes the struct in the layout of BinarySerializer, without reflection.
+type GeneratedMarshaler interface {
+	MarshalBinaryTo(w *Writer) error
+}
+
+// GeneratedUnmarshaler is implemented by the structs serializergen generated methods for.
+// UnmarshalBinaryFrom reads the struct from the layout of BinarySerializer, without reflection.
+type GeneratedUnmarshaler interface {
+	UnmarshalBinaryFrom(r *Reader) error
+}
+
+var (
+	generatedMarshalerType   = reflect.TypeOf((*GeneratedMarshaler)(nil)).Elem()
+	generatedUnmarshalerType = reflect.TypeOf((*GeneratedUnmarshaler)(nil)).Elem()
+
+	// generatedLayout writes and reads the fields generated methods hand over to Writer.WriteValue and
+	// Reader.ReadValue.
+	generatedLayout = NewBinarySerializer()
+)
+
+func init() {
+	binaryx.RegisterGenerated(generatedCodecOf)
+}
+
+// generatedCodecOf returns the codec calling the generated methods of the struct type typ, whose pointer must
+// implement both GeneratedMarshaler and GeneratedUnmarshaler.
+//
+// The binary serializers call generated methods whenever their options keep the default layout; registered codecs
+// and marshaler hooks still take precedence over them.
+func generatedCodecOf(typ reflect.Type) (binaryx.Codec, bool) {
+	ptr := reflect.PointerTo(typ)
+	if !ptr.Implements(generatedMarshalerType) || !ptr.Implements(generatedUnmarshalerType) {
+		return binaryx.Codec{}, false
+	}
+
+	return binaryx.Codec{
+		Encode: func(bbw *bytesx.Writer, value reflect.Value) error {
+			if !value.CanAddr() {
+				addressable := reflect.New(typ).Elem()
+				addressable.Set(value)
+				value = addressable
+			}
+
+			return value.Addr().Interface().(GeneratedMarshaler).MarshalBinaryTo(&Writer{bbw: bbw})
+		},
+		Decode: func(bbr *bytesx.Reader, value reflect.Value) error {
+			return value.Addr().Interface().(GeneratedUnmarshaler).UnmarshalBinaryFrom(&Reader{bbr: bbr})
+		},
+	}, true
+}
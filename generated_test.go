@@ -0,0 +1,230 @@
+//go:build unit
+
+package serializer_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/gentestmodels"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/serializerx"
+)
+
+// probe counts the calls to its hand-written generated methods.
+type probe struct {
+	N uint32 `binary:",id=1"`
+}
+
+var probeCalls struct {
+	marshal, unmarshal int
+}
+
+func (p *probe) MarshalBinaryTo(w *serializer.Writer) error {
+	probeCalls.marshal++
+	w.WriteUint32(p.N)
+	return nil
+}
+
+func (p *probe) UnmarshalBinaryFrom(r *serializer.Reader) error {
+	probeCalls.unmarshal++
+	n, err := r.ReadUint32()
+	p.N = n
+	return err
+}
+
+func generatedSerializers(opts ...serializer.BinaryOption) map[string]models.Serializer {
+	return map[string]models.Serializer{
+		"binary":     serializer.NewBinarySerializer(opts...),
+		"raw binary": serializer.NewRawBinarySerializer(opts...),
+	}
+}
+
+func allGeneratedSerializers(opts ...serializer.BinaryOption) map[string]models.Serializer {
+	s := generatedSerializers(opts...)
+	s["serializerx binary"] = serializerx.NewBinarySerializer(opts...)
+	return s
+}
+
+func newGeneratedOrder() (gentestmodels.Order, gentestmodels.ReflectedOrder) {
+	coupon := "WELCOME10"
+	createdAt := time.Date(2024, 3, 9, 12, 30, 0, 0, time.UTC)
+
+	order := gentestmodels.Order{
+		ID:        42,
+		Revision:  -3,
+		Customer:  "ada",
+		Status:    "paid",
+		Paid:      true,
+		Total:     99.95,
+		Discount:  0.1,
+		Offset:    complex(1.5, -2),
+		Priority:  -7,
+		Region:    513,
+		Tags:      gentestmodels.Tags{"fragile", "express"},
+		Notes:     []string{"", "ring twice"},
+		Signature: []byte{0xde, 0xad, 0xbe, 0xef},
+		Checksum:  [4]byte{1, 2, 3, 4},
+		Grid:      [2][2]int16{{1, -1}, {256, -256}},
+		Lines: []gentestmodels.OrderLine{
+			{SKU: "A-1", Qty: 2, Chunks: [][]byte{{1}, nil, {2, 3}}},
+			{SKU: "B-2", Qty: 1},
+		},
+		Gift:      &gentestmodels.OrderLine{SKU: "G-0", Qty: 1},
+		Coupon:    &coupon,
+		ByRef:     map[string]gentestmodels.OrderLine{"A-1": {SKU: "A-1", Qty: 2}},
+		Counts:    map[int]int{7: -7},
+		Extra:     map[string]interface{}{"rush": true},
+		Meta:      int64(-9),
+		CreatedAt: createdAt,
+		TTL:       time.Hour,
+		Cache:     []byte("skipped"),
+	}
+	order.SetInternal(11)
+
+	reflected := gentestmodels.ReflectedOrder{
+		ID:        order.ID,
+		Revision:  order.Revision,
+		Customer:  order.Customer,
+		Status:    order.Status,
+		Paid:      order.Paid,
+		Total:     order.Total,
+		Discount:  order.Discount,
+		Offset:    order.Offset,
+		Priority:  order.Priority,
+		Region:    order.Region,
+		Tags:      order.Tags,
+		Notes:     order.Notes,
+		Signature: order.Signature,
+		Checksum:  order.Checksum,
+		Grid:      order.Grid,
+		Lines: []gentestmodels.ReflectedOrderLine{
+			{SKU: "A-1", Qty: 2, Chunks: [][]byte{{1}, nil, {2, 3}}},
+			{SKU: "B-2", Qty: 1},
+		},
+		Gift:      &gentestmodels.ReflectedOrderLine{SKU: "G-0", Qty: 1},
+		Coupon:    order.Coupon,
+		ByRef:     map[string]gentestmodels.ReflectedOrderLine{"A-1": {SKU: "A-1", Qty: 2}},
+		Counts:    order.Counts,
+		Extra:     order.Extra,
+		Meta:      order.Meta,
+		CreatedAt: createdAt,
+		TTL:       order.TTL,
+		Cache:     order.Cache,
+	}
+
+	return order, reflected
+}
+
+func TestGeneratedMethods(t *testing.T) {
+	t.Run("same payload as reflection", func(t *testing.T) {
+		for name, s := range allGeneratedSerializers() {
+			t.Run(name, func(t *testing.T) {
+				order, reflected := newGeneratedOrder()
+
+				generated, err := s.Serialize(&order)
+				require.NoError(t, err)
+
+				want, err := s.Serialize(&reflected)
+				require.NoError(t, err)
+				assert.Equal(t, want, generated)
+
+				bs, err := s.Serialize([]*gentestmodels.Order{&order, nil})
+				require.NoError(t, err)
+
+				want, err = s.Serialize([]*gentestmodels.ReflectedOrder{&reflected, nil})
+				require.NoError(t, err)
+				assert.Equal(t, want, bs)
+			})
+		}
+	})
+
+	t.Run("cross decoding", func(t *testing.T) {
+		for name, s := range allGeneratedSerializers() {
+			t.Run(name, func(t *testing.T) {
+				order, reflected := newGeneratedOrder()
+				order.SetInternal(0)
+				order.Cache, reflected.Cache = nil, nil
+				order.Counts = map[int]int{1: 1, 2: -2, 3: 3}
+				reflected.Counts = order.Counts
+
+				bs, err := s.Serialize(&reflected)
+				require.NoError(t, err)
+
+				var decoded gentestmodels.Order
+				require.NoError(t, s.Deserialize(bs, &decoded))
+				assert.Equal(t, order, decoded)
+
+				bs, err = s.Serialize(&order)
+				require.NoError(t, err)
+
+				var decodedReflected gentestmodels.ReflectedOrder
+				require.NoError(t, s.Deserialize(bs, &decodedReflected))
+				assert.Equal(t, reflected, decodedReflected)
+			})
+		}
+	})
+
+	t.Run("truncated payload", func(t *testing.T) {
+		for name, s := range allGeneratedSerializers() {
+			t.Run(name, func(t *testing.T) {
+				order, _ := newGeneratedOrder()
+
+				bs, err := s.Serialize(&order)
+				require.NoError(t, err)
+
+				for _, n := range []int{0, 3, len(bs) / 2, len(bs) - 1} {
+					var decoded gentestmodels.Order
+					assert.Error(t, s.Deserialize(bs[:n], &decoded), "%d bytes", n)
+				}
+			})
+		}
+	})
+
+	t.Run("detected in nested values", func(t *testing.T) {
+		for name, s := range allGeneratedSerializers() {
+			t.Run(name, func(t *testing.T) {
+				probeCalls.marshal, probeCalls.unmarshal = 0, 0
+
+				bs, err := s.Serialize(map[string][]probe{"a": {{N: 1}, {N: 2}}})
+				require.NoError(t, err)
+
+				var decoded map[string][]probe
+				require.NoError(t, s.Deserialize(bs, &decoded))
+				assert.Equal(t, map[string][]probe{"a": {{N: 1}, {N: 2}}}, decoded)
+				assert.Equal(t, 2, probeCalls.marshal)
+				assert.Equal(t, 2, probeCalls.unmarshal)
+			})
+		}
+	})
+
+	t.Run("not used by other layouts", func(t *testing.T) {
+		layouts := map[string]serializer.BinaryOption{
+			"varint":      serializer.WithVarint(),
+			"field ids":   serializer.WithFieldIDs(),
+			"unexported":  serializer.WithUnexportedFields(serializer.IncludeUnexportedFields),
+			"unsupported": serializer.WithSkipUnsupportedFields(),
+		}
+
+		for layout, opt := range layouts {
+			for name, s := range generatedSerializers(opt) {
+				t.Run(layout+"/"+name, func(t *testing.T) {
+					probeCalls.marshal, probeCalls.unmarshal = 0, 0
+
+					bs, err := s.Serialize(&probe{N: 300})
+					require.NoError(t, err)
+
+					var decoded probe
+					require.NoError(t, s.Deserialize(bs, &decoded))
+					assert.Equal(t, probe{N: 300}, decoded)
+					assert.Zero(t, probeCalls.marshal)
+					assert.Zero(t, probeCalls.unmarshal)
+				})
+			}
+		}
+	})
+}
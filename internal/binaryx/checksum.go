@@ -0,0 +1,34 @@
+package binaryx
+
+import (
+	"hash/crc32"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/bytesx"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
+)
+
+// ChecksumSize is the length of the checksum trailer.
+const ChecksumSize = 4
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// PutChecksum appends the CRC32C of everything written to bbw from offset start.
+func PutChecksum(bbw *bytesx.Writer, start int) {
+	bbw.Write(bytesx.AddUint32(crc32.Checksum(bbw.Bytes()[start:], castagnoli)))
+}
+
+// ReadChecksum removes the trailer written by PutChecksum from bbr and verifies it against the whole payload,
+// including the bytes already read. It fails with a *models.ChecksumError when they do not match.
+func ReadChecksum(bbr *bytesx.Reader) error {
+	trailer := bbr.TrimTrailer(ChecksumSize)
+	if trailer == nil {
+		return bbr.Err()
+	}
+
+	actual := crc32.Update(crc32.Checksum(bbr.Bytes(), castagnoli), castagnoli, bbr.BytesFromCursor())
+	if expected := bytesx.Uint32(trailer); expected != actual {
+		return &models.ChecksumError{Expected: expected, Actual: actual}
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package binaryx
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/bytesx"
+)
+
+// Codec holds the custom functions serializing a single type.
+type Codec struct {
+	// Encode writes value into bbw.
+	Encode func(bbw *bytesx.Writer, value reflect.Value) error
+	// Decode reads from bbr into the addressable value.
+	Decode func(bbr *bytesx.Reader, value reflect.Value) error
+}
+
+var codecs = struct {
+	sync.Mutex
+
+	// registered holds a map[reflect.Type]Codec that is copied on every registration,
+	// so that lookups never take the lock.
+	registered atomic.Value
+}{}
+
+func init() {
+	codecs.registered.Store(map[reflect.Type]Codec{})
+}
+
+// RegisterCodec makes the binary serializers use codec for every value of type typ.
+//
+// Codecs take precedence over marshaler hooks and the built-in kind switches. They cannot be registered for
+// predeclared, pointer or interface types; pointers to typ are handled by the serializers before calling codec.
+// RegisterCodec panics when typ is not allowed or already has a codec.
+func RegisterCodec(typ reflect.Type, codec Codec) {
+	switch {
+	case typ == nil:
+		panic("binary: registering codec for nil type")
+	case typ.Name() != "" && typ.PkgPath() == "":
+		panic(fmt.Sprintf("binary: registering codec for predeclared type %s", typ))
+	case typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Interface:
+		panic(fmt.Sprintf("binary: registering codec for %s type %s", typ.Kind(), typ))
+	case codec.Encode == nil || codec.Decode == nil:
+		panic(fmt.Sprintf("binary: registering incomplete codec for %s", typ))
+	}
+
+	codecs.Lock()
+	defer codecs.Unlock()
+
+	current := codecs.registered.Load().(map[reflect.Type]Codec)
+	if _, ok := current[typ]; ok {
+		panic(fmt.Sprintf("binary: registering duplicate codec for %s", typ))
+	}
+
+	next := make(map[reflect.Type]Codec, len(current)+1)
+	for t, c := range current {
+		next[t] = c
+	}
+	next[typ] = codec
+
+	codecs.registered.Store(next)
+	resetPlans()
+}
+
+// CodecOf returns the codec registered for typ.
+func CodecOf(typ reflect.Type) (Codec, bool) {
+	codec, ok := codecs.registered.Load().(map[reflect.Type]Codec)[typ]
+	return codec, ok
+}
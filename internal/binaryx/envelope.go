@@ -0,0 +1,125 @@
+package binaryx
+
+import (
+	"fmt"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/bytesx"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
+)
+
+// The envelope header is made of the magic bytes, the format, the version and the flags, one byte each.
+const (
+	// EnvelopeVersion is the revision of the binary format written by the serializers.
+	EnvelopeVersion uint8 = 1
+	// EnvelopeSize is the length of the envelope header.
+	EnvelopeSize = len(envelopeMagic) + 3
+)
+
+var envelopeMagic = [3]byte{'G', 'S', 'B'}
+
+// Format identifies the serializer that wrote an enveloped payload.
+type Format uint8
+
+const (
+	FormatBinary Format = iota + 1
+	FormatRawBinary
+	FormatBinaryX
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatBinary:
+		return "serializer.BinarySerializer"
+	case FormatRawBinary:
+		return "serializer.RawBinarySerializer"
+	case FormatBinaryX:
+		return "serializerx.BinarySerializer"
+	default:
+		return fmt.Sprintf("unknown format %d", uint8(f))
+	}
+}
+
+// Envelope flags describe how the payload following the header was written. Compression has no flag: the
+// envelope sits inside the payloads of CompressionSerializer, which starts them with the marker of their algorithm.
+const (
+	// FlagVarint marks payloads written in varint mode.
+	FlagVarint uint8 = 1 << iota
+	// FlagChecksum marks payloads followed by a checksum.
+	FlagChecksum
+	// the third bit is reserved, so that the flags below keep their value in payloads already written
+	_
+	// FlagFieldIDs marks payloads written in the field id mode.
+	FlagFieldIDs
+	// FlagSchemaFingerprint marks payloads starting with a schema fingerprint.
+	FlagSchemaFingerprint
+
+	supportedFlags = FlagVarint | FlagChecksum | FlagFieldIDs | FlagSchemaFingerprint
+)
+
+// EnvelopeFlags returns the flags describing payloads written with o.
+func (o Options) EnvelopeFlags() uint8 {
+	var flags uint8
+	if o.Varint {
+		flags |= FlagVarint
+	}
+
+	if o.Checksum {
+		flags |= FlagChecksum
+	}
+
+	if o.FieldIDs {
+		flags |= FlagFieldIDs
+	}
+
+	if o.SchemaFingerprint {
+		flags |= FlagSchemaFingerprint
+	}
+
+	return flags
+}
+
+// PutEnvelope writes the envelope header of a payload written by format.
+func PutEnvelope(bbw *bytesx.Writer, format Format, flags uint8) {
+	bbw.Write(envelopeMagic[:])
+	bbw.Put(byte(format))
+	bbw.Put(EnvelopeVersion)
+	bbw.Put(flags)
+}
+
+// ReadEnvelope reads the header written by PutEnvelope and returns its flags.
+// It fails with a *models.EnvelopeError when the payload was not written by format in the current version.
+func ReadEnvelope(bbr *bytesx.Reader, format Format) (uint8, error) {
+	if bbr.Len() < len(envelopeMagic) || [3]byte(bbr.BytesFromCursor()) != envelopeMagic {
+		return 0, &models.EnvelopeError{Err: models.ErrNotEnveloped}
+	}
+
+	header := bbr.Read(EnvelopeSize)
+	if header == nil {
+		return 0, bbr.Err()
+	}
+
+	// the version is checked first as later revisions may lay out the rest of the header differently
+	if version := header[4]; version != EnvelopeVersion {
+		return 0, &models.EnvelopeError{
+			Err:    models.ErrUnsupportedVersion,
+			Detail: fmt.Sprintf("got version %d, want %d", version, EnvelopeVersion),
+		}
+	}
+
+	if found := Format(header[3]); found != format {
+		return 0, &models.EnvelopeError{
+			Err:    models.ErrFormatMismatch,
+			Detail: fmt.Sprintf("written by %s, read by %s", found, format),
+		}
+	}
+
+	flags := header[5]
+	if unknown := flags &^ supportedFlags; unknown != 0 {
+		return 0, &models.EnvelopeError{
+			Err:    models.ErrUnsupportedFlags,
+			Detail: fmt.Sprintf("%#x", unknown),
+		}
+	}
+
+	return flags, nil
+}
@@ -0,0 +1,174 @@
+package binaryx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/bytesx"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/reflectx"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
+)
+
+// In the field id mode every struct field is preceded by a key holding its id and wire type, written as an
+// unsigned varint of id<<3 | wire type. The wire type tells decoders how to skip fields they do not know about.
+// A zero key ends the struct.
+
+// MaxFieldID is the largest id accepted by the id tag option.
+const MaxFieldID = 1<<29 - 1
+
+var errMissingFieldID = errors.New("missing id option, required by the field id mode")
+
+// WireType describes how a field is laid out in the field id mode.
+type WireType uint8
+
+const (
+	// WireVarint fields are a single varint.
+	WireVarint WireType = iota
+	// WireFixed8 fields take a single byte.
+	WireFixed8
+	// WireFixed16 fields take two bytes.
+	WireFixed16
+	// WireFixed32 fields take four bytes.
+	WireFixed32
+	// WireFixed64 fields take eight bytes.
+	WireFixed64
+	// WireBytes fields are prefixed with their uint32 length.
+	WireBytes
+)
+
+func (wt WireType) String() string {
+	switch wt {
+	case WireVarint:
+		return "varint"
+	case WireFixed8:
+		return "fixed8"
+	case WireFixed16:
+		return "fixed16"
+	case WireFixed32:
+		return "fixed32"
+	case WireFixed64:
+		return "fixed64"
+	case WireBytes:
+		return "bytes"
+	default:
+		return fmt.Sprintf("wire type %d", uint8(wt))
+	}
+}
+
+// WireTypeOf returns the wire type of fields of type typ, varint telling whether integers are written as varints.
+func WireTypeOf(typ reflect.Type, varint bool) WireType {
+	if hasHooks(typ) {
+		return WireBytes
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool, reflect.Int8, reflect.Uint8:
+		return WireFixed8
+	case reflect.Int16, reflect.Uint16:
+		if varint {
+			return WireVarint
+		}
+
+		return WireFixed16
+	case reflect.Int32, reflect.Uint32:
+		if varint {
+			return WireVarint
+		}
+
+		return WireFixed32
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		if varint {
+			return WireVarint
+		}
+
+		return WireFixed64
+	case reflect.Float32:
+		return WireFixed32
+	case reflect.Float64, reflect.Complex64:
+		return WireFixed64
+	default:
+		return WireBytes
+	}
+}
+
+// PutFieldKey writes the key announcing the field id with wire type wt.
+func PutFieldKey(bbw *bytesx.Writer, id int, wt WireType) {
+	bbw.PutUvarint(uint64(id)<<3 | uint64(wt))
+}
+
+// PutEndOfFields writes the key ending a struct.
+func PutEndOfFields(bbw *bytesx.Writer) {
+	bbw.Put(0)
+}
+
+// ReadFieldKey reads a key written by PutFieldKey. It returns a zero id at the end of the struct.
+func ReadFieldKey(bbr *bytesx.Reader) (int, WireType) {
+	offset := bbr.Yield()
+	key := bbr.Uvarint()
+	if key == 0 {
+		return 0, 0
+	}
+
+	id, wt := key>>3, WireType(key&7)
+	if id == 0 || id > MaxFieldID || wt > WireBytes {
+		bbr.Fail(&models.DecodeError{Offset: offset, Reason: fmt.Sprintf("invalid field key %#x", key)})
+		return 0, 0
+	}
+
+	return int(id), wt
+}
+
+// SkipField moves bbr past a field of wire type wt.
+func SkipField(bbr *bytesx.Reader, wt WireType) {
+	switch wt {
+	case WireVarint:
+		bbr.Uvarint()
+	case WireFixed8:
+		bbr.Skip(1)
+	case WireFixed16:
+		bbr.Skip(2)
+	case WireFixed32:
+		bbr.Skip(4)
+	case WireFixed64:
+		bbr.Skip(8)
+	case WireBytes:
+		bbr.Skip(int(bbr.Uint32()))
+	}
+}
+
+// FieldPlanByID returns the plan of the field identified by id, or nil.
+func FieldPlanByID(fields []FieldPlan, id int) *FieldPlan {
+	for i := range fields {
+		if fields[i].ID == id {
+			return &fields[i]
+		}
+	}
+
+	return nil
+}
+
+// ZeroFields zeroes the fields of the struct value, so that the ones missing from a payload decoded in the field
+// id mode do not keep the values a reused target held before.
+func ZeroFields(value reflect.Value, fields []FieldPlan) {
+	for i := range fields {
+		f := value.Field(fields[i].Index)
+		if !fields[i].Exported {
+			f = reflectx.Exported(f)
+		}
+
+		f.SetZero()
+	}
+}
+
+// MissingFieldIDError returns the error reported for the field sf of the struct type typ when it has no id
+// to be written with in the field id mode.
+func MissingFieldIDError(typ reflect.Type, sf Field) error {
+	field := typ.Field(sf.Index)
+	return &models.StructTagError{
+		Type:  typ,
+		Field: field.Name,
+		Tag:   field.Tag.Get(TagName),
+		Err:   errMissingFieldID,
+	}
+}
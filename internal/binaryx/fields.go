@@ -0,0 +1,155 @@
+package binaryx
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
+)
+
+// TagName is the struct tag key read by the binary serializers.
+//
+// The tag value follows the encoding/json style: an optional name followed by comma separated options.
+//
+//	Field int `binary:"-"`          // never serialized
+//	Field int `binary:"name"`       // serialized as "name" in error paths and schema metadata
+//	Field int `binary:",order=2"`   // pinned at wire position 2
+//	Field int `binary:",id=3"`      // identified by 3 in the field id mode
+const TagName = "binary"
+
+// Field describes how a struct field is laid out in the binary format.
+type Field struct {
+	// Index is the field's index within its struct.
+	Index int
+	// Name is the field's binary name; the tag name when given, the Go name otherwise.
+	Name string
+	// Type is the field's Go type.
+	Type reflect.Type
+	// Order is the wire position pinned by the order tag option, or -1 when there is none.
+	Order int
+	// ID identifies the field in the field id mode. It is 0 when the field has no id tag option.
+	ID int
+	// Unsupported is the kind preventing the field from being serialized, or reflect.Invalid.
+	Unsupported reflect.Kind
+	// Exported reports whether the field is exported.
+	Exported bool
+}
+
+type structFields struct {
+	fields []Field
+	err    error
+}
+
+var structFieldsCache sync.Map // map[reflect.Type]structFields
+
+// StructFields returns the fields of the struct type typ in wire order.
+//
+// Fields tagged with `binary:"-"` are left out. Fields with an order option come first, sorted by it;
+// the remaining ones follow in declaration order. The result is cached per type.
+func StructFields(typ reflect.Type) ([]Field, error) {
+	if cached, ok := structFieldsCache.Load(typ); ok {
+		sf := cached.(structFields)
+		return sf.fields, sf.err
+	}
+
+	fields, err := parseStructFields(typ)
+	structFieldsCache.Store(typ, structFields{fields: fields, err: err})
+
+	return fields, err
+}
+
+func parseStructFields(typ reflect.Type) ([]Field, error) {
+	limit := typ.NumField()
+	fields := make([]Field, 0, limit)
+	for idx := 0; idx < limit; idx++ {
+		sf := typ.Field(idx)
+		tag, hasTag := sf.Tag.Lookup(TagName)
+		if tag == "-" {
+			continue
+		}
+
+		field := Field{
+			Index:       idx,
+			Name:        sf.Name,
+			Type:        sf.Type,
+			Order:       -1,
+			Unsupported: UnsupportedKind(sf.Type),
+			Exported:    sf.IsExported(),
+		}
+
+		if hasTag {
+			if err := parseTag(&field, tag); err != nil {
+				return nil, &models.StructTagError{Type: typ, Field: sf.Name, Tag: tag, Err: err}
+			}
+		}
+
+		fields = append(fields, field)
+	}
+
+	ids := make(map[int]string)
+	for _, field := range fields {
+		if field.ID == 0 {
+			continue
+		}
+
+		if name, ok := ids[field.ID]; ok {
+			sf := typ.Field(field.Index)
+			return nil, &models.StructTagError{
+				Type:  typ,
+				Field: sf.Name,
+				Tag:   sf.Tag.Get(TagName),
+				Err:   fmt.Errorf("id %d already used by field %s", field.ID, name),
+			}
+		}
+
+		ids[field.ID] = typ.Field(field.Index).Name
+	}
+
+	sort.SliceStable(fields, func(i, j int) bool {
+		if fields[i].Order < 0 || fields[j].Order < 0 {
+			return fields[i].Order >= 0 && fields[j].Order < 0
+		}
+
+		return fields[i].Order < fields[j].Order
+	})
+
+	return fields, nil
+}
+
+func parseTag(field *Field, tag string) error {
+	name, opts, _ := strings.Cut(tag, ",")
+	if name != "" {
+		field.Name = name
+	}
+
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+
+		key, value, _ := strings.Cut(opt, "=")
+		switch key {
+		case "order":
+			order, err := strconv.Atoi(value)
+			if err != nil || order < 0 {
+				return fmt.Errorf("invalid order %q", value)
+			}
+
+			field.Order = order
+		case "id":
+			id, err := strconv.Atoi(value)
+			if err != nil || id <= 0 || id > MaxFieldID {
+				return fmt.Errorf("invalid id %q", value)
+			}
+
+			field.ID = id
+		default:
+			return fmt.Errorf("unknown option %q", key)
+		}
+	}
+
+	return nil
+}
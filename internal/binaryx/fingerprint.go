@@ -0,0 +1,147 @@
+package binaryx
+
+import (
+	"hash/fnv"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/bytesx"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
+)
+
+type fingerprintKey struct {
+	typ              reflect.Type
+	unexportedFields UnexportedFieldPolicy
+	skipUnsupported  bool
+}
+
+var fingerprintCache sync.Map // map[fingerprintKey]uint64
+
+// Fingerprint returns a stable hash of the layout of typ as written with o: the names, ids, kinds and order of the
+// struct fields it is made of, down to the nested types. Renaming a Go type keeps its fingerprint, while renaming,
+// reordering, adding or removing a serialized field changes it. Pointers are ignored at the top level like the
+// serializers do.
+func Fingerprint(typ reflect.Type, o Options) uint64 {
+	if typ == nil {
+		return 0
+	}
+
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	key := fingerprintKey{typ: typ, unexportedFields: o.UnexportedFields, skipUnsupported: o.SkipUnsupported}
+	if fp, ok := fingerprintCache.Load(key); ok {
+		return fp.(uint64)
+	}
+
+	var sb strings.Builder
+	describeLayout(&sb, typ, o, nil)
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(sb.String()))
+	fp := h.Sum64()
+
+	fingerprintCache.Store(key, fp)
+	return fp
+}
+
+// describeLayout writes the canonical description of typ hashed by Fingerprint.
+// structs holds the struct types being described, so that recursive types refer back to them.
+func describeLayout(sb *strings.Builder, typ reflect.Type, o Options, structs []reflect.Type) {
+	if _, ok := CodecOf(typ); ok {
+		sb.WriteString("codec(" + typ.String() + ")")
+		return
+	}
+
+	if MarshalerOf(typ) != NoMarshaler {
+		sb.WriteString("marshaler(" + typ.String() + ")")
+		return
+	}
+
+	switch typ.Kind() {
+	case reflect.Ptr:
+		sb.WriteString("*")
+		describeLayout(sb, typ.Elem(), o, structs)
+	case reflect.Slice:
+		sb.WriteString("[]")
+		describeLayout(sb, typ.Elem(), o, structs)
+	case reflect.Array:
+		sb.WriteString("[" + strconv.Itoa(typ.Len()) + "]")
+		describeLayout(sb, typ.Elem(), o, structs)
+	case reflect.Map:
+		sb.WriteString("map[")
+		describeLayout(sb, typ.Key(), o, structs)
+		sb.WriteString("]")
+		describeLayout(sb, typ.Elem(), o, structs)
+	case reflect.Struct:
+		for depth, st := range structs {
+			if st == typ {
+				sb.WriteString("recursive(" + strconv.Itoa(len(structs)-depth) + ")")
+				return
+			}
+		}
+
+		describeStruct(sb, typ, o, append(structs, typ))
+	default:
+		// named types keep the layout of their kind
+		sb.WriteString(typ.Kind().String())
+	}
+}
+
+func describeStruct(sb *strings.Builder, typ reflect.Type, o Options, structs []reflect.Type) {
+	fields, err := StructFields(typ)
+	if err != nil {
+		// the serializers fail on such types anyway
+		sb.WriteString("invalid(" + typ.String() + ")")
+		return
+	}
+
+	sb.WriteString("struct{")
+	for _, sf := range fields {
+		if !sf.Exported && o.UnexportedFields == SkipUnexported {
+			continue
+		}
+
+		if sf.Unsupported != reflect.Invalid && o.SkipUnsupported {
+			continue
+		}
+
+		sb.WriteString(sf.Name)
+		if sf.ID != 0 {
+			sb.WriteString("=" + strconv.Itoa(sf.ID))
+		}
+
+		sb.WriteString(" ")
+		describeLayout(sb, sf.Type, o, structs)
+		sb.WriteString(";")
+	}
+
+	sb.WriteString("}")
+}
+
+// PutFingerprint writes the fingerprint of typ.
+func PutFingerprint(bbw *bytesx.Writer, typ reflect.Type, o Options) {
+	bbw.Write(bytesx.AddUint64(Fingerprint(typ, o)))
+}
+
+// ReadFingerprint reads a fingerprint written by PutFingerprint, failing with a *models.SchemaMismatchError
+// when it differs from the fingerprint of typ.
+func ReadFingerprint(bbr *bytesx.Reader, typ reflect.Type, o Options) error {
+	payload := bbr.Uint64()
+	if err := bbr.Err(); err != nil {
+		return err
+	}
+
+	if want := Fingerprint(typ, o); payload != want {
+		if typ != nil && typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+
+		return &models.SchemaMismatchError{Type: typ, Fingerprint: want, Payload: payload}
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package binaryx
+
+import (
+	"reflect"
+	"sync"
+)
+
+// generated holds the function building the codecs of the types with methods generated by serializergen.
+// It is set by the serializer package, which the generated methods import.
+var generated struct {
+	codecOf func(typ reflect.Type) (Codec, bool)
+	codecs  sync.Map // map[reflect.Type]generatedCodec
+}
+
+type generatedCodec struct {
+	codec Codec
+	ok    bool
+}
+
+// RegisterGenerated sets the function returning the codec calling the generated methods of a type.
+func RegisterGenerated(codecOf func(typ reflect.Type) (Codec, bool)) {
+	generated.codecOf = codecOf
+}
+
+// GeneratedCodecOf returns the codec calling the methods serializergen generated for typ. The generated methods
+// lay values out like the serializers do with the options reporting GeneratedLayout. The result is cached per type.
+func GeneratedCodecOf(typ reflect.Type) (Codec, bool) {
+	if generated.codecOf == nil || typ.Kind() != reflect.Struct {
+		return Codec{}, false
+	}
+
+	if cached, ok := generated.codecs.Load(typ); ok {
+		gc := cached.(generatedCodec)
+		return gc.codec, gc.ok
+	}
+
+	codec, ok := generated.codecOf(typ)
+	generated.codecs.Store(typ, generatedCodec{codec: codec, ok: ok})
+	return codec, ok
+}
+
+// GeneratedLayout reports whether o lays values out the way the methods generated by serializergen do: fixed-width
+// integers, positional fields and exported fields only, failing on unsupported ones.
+func (o Options) GeneratedLayout() bool {
+	return !o.Varint && !o.FieldIDs && o.UnexportedFields == SkipUnexported && !o.SkipUnsupported
+}
@@ -0,0 +1,64 @@
+package binaryx
+
+import (
+	"reflect"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
+)
+
+// UnsupportedKind returns the kind that prevents typ from being represented in the binary format,
+// or reflect.Invalid when typ is supported.
+//
+// Pointers, slices, arrays and maps are unsupported whenever their element types are.
+// Structs are always supported at the type level; their fields are checked one by one.
+// Types serialized through a registered codec or their marshalers are always supported.
+func UnsupportedKind(typ reflect.Type) reflect.Kind {
+	if hasHooks(typ) {
+		return reflect.Invalid
+	}
+
+	switch typ.Kind() {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Uintptr:
+		return typ.Kind()
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return UnsupportedKind(typ.Elem())
+	case reflect.Map:
+		if kind := UnsupportedKind(typ.Key()); kind != reflect.Invalid {
+			return kind
+		}
+
+		return UnsupportedKind(typ.Elem())
+	default:
+		return reflect.Invalid
+	}
+}
+
+// Pointee returns the value the non-nil pointer v points to, checking it can be serialized.
+func Pointee(v interface{}) (reflect.Value, error) {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return reflect.Value{}, &models.InvalidTargetError{Type: reflect.TypeOf(v)}
+	}
+
+	value = value.Elem()
+	if kind := UnsupportedKind(value.Type()); kind != reflect.Invalid {
+		return reflect.Value{}, &models.UnsupportedTypeError{Type: value.Type(), Kind: kind}
+	}
+
+	return value, nil
+}
+
+// DecodeTarget is Pointee for the targets payloads are decoded into, which cannot point to nil pointers either:
+// the binary serializers read and write the values pointers point to, not the pointers themselves.
+func DecodeTarget(target interface{}) (reflect.Value, error) {
+	value, err := Pointee(target)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if value.Kind() == reflect.Ptr && value.IsNil() {
+		return reflect.Value{}, &models.InvalidTargetError{Type: reflect.TypeOf(target)}
+	}
+
+	return value, nil
+}
@@ -0,0 +1,111 @@
+package binaryx
+
+import (
+	"encoding"
+	"reflect"
+	"sync"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
+)
+
+// Marshaler identifies the encoding interfaces a type is serialized through.
+type Marshaler uint8
+
+const (
+	// NoMarshaler means the type is walked reflectively.
+	NoMarshaler Marshaler = iota
+	// BinaryMarshaler means the type implements encoding.BinaryMarshaler and encoding.BinaryUnmarshaler.
+	BinaryMarshaler
+	// TextMarshaler means the type implements encoding.TextMarshaler and encoding.TextUnmarshaler.
+	TextMarshaler
+)
+
+var (
+	binaryMarshalerType   = reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem()
+	binaryUnmarshalerType = reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem()
+	textMarshalerType     = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType   = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+var marshalerCache sync.Map // map[reflect.Type]Marshaler
+
+// MarshalerOf returns the encoding interfaces typ is serialized through.
+//
+// A type qualifies when both halves of the pair are implemented, with either value or pointer receivers;
+// encoding.BinaryMarshaler is preferred over encoding.TextMarshaler. Pointer and interface types never
+// qualify, their elements and concrete values are checked instead. The result is cached per type.
+func MarshalerOf(typ reflect.Type) Marshaler {
+	if typ.Name() != "" && typ.PkgPath() == "" {
+		// predeclared types have no methods
+		return NoMarshaler
+	}
+
+	if cached, ok := marshalerCache.Load(typ); ok {
+		return cached.(Marshaler)
+	}
+
+	m := NoMarshaler
+	if typ.Kind() != reflect.Ptr && typ.Kind() != reflect.Interface {
+		ptr := reflect.PointerTo(typ)
+		switch {
+		case ptr.Implements(binaryMarshalerType) && ptr.Implements(binaryUnmarshalerType):
+			m = BinaryMarshaler
+		case ptr.Implements(textMarshalerType) && ptr.Implements(textUnmarshalerType):
+			m = TextMarshaler
+		}
+	}
+
+	marshalerCache.Store(typ, m)
+	return m
+}
+
+// Marshal calls the marshaler reported by MarshalerOf on value.
+func Marshal(value reflect.Value) ([]byte, error) {
+	typ := value.Type()
+	if !value.CanAddr() {
+		// pointer receivers need an addressable value
+		addressable := reflect.New(typ).Elem()
+		addressable.Set(value)
+		value = addressable
+	}
+
+	switch MarshalerOf(typ) {
+	case BinaryMarshaler:
+		bs, err := value.Addr().Interface().(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			return nil, &models.MarshalerError{Type: typ, Method: "MarshalBinary", Err: err}
+		}
+
+		return bs, nil
+	case TextMarshaler:
+		bs, err := value.Addr().Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, &models.MarshalerError{Type: typ, Method: "MarshalText", Err: err}
+		}
+
+		return bs, nil
+	default:
+		return nil, &models.UnsupportedTypeError{Type: typ, Kind: typ.Kind()}
+	}
+}
+
+// Unmarshal calls the unmarshaler reported by MarshalerOf on the addressable value.
+func Unmarshal(value reflect.Value, data []byte) error {
+	typ := value.Type()
+	switch MarshalerOf(typ) {
+	case BinaryMarshaler:
+		if err := value.Addr().Interface().(encoding.BinaryUnmarshaler).UnmarshalBinary(data); err != nil {
+			return &models.MarshalerError{Type: typ, Method: "UnmarshalBinary", Err: err}
+		}
+
+		return nil
+	case TextMarshaler:
+		if err := value.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText(data); err != nil {
+			return &models.MarshalerError{Type: typ, Method: "UnmarshalText", Err: err}
+		}
+
+		return nil
+	default:
+		return &models.UnsupportedTypeError{Type: typ, Kind: typ.Kind()}
+	}
+}
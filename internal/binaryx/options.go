@@ -0,0 +1,103 @@
+package binaryx
+
+// Options holds the settings shared by the binary serializers.
+type Options struct {
+	// SkipUnsupported makes the serializers leave out fields whose kind cannot be represented in the
+	// binary format instead of failing with a *models.UnsupportedTypeError.
+	SkipUnsupported bool
+	// UnexportedFields decides what happens to unexported struct fields.
+	UnexportedFields UnexportedFieldPolicy
+	// Varint makes the serializers write multi-byte integers and lengths as LEB128 varints,
+	// zigzag encoding the signed ones.
+	Varint bool
+	// Envelope makes the serializers prefix their payloads with a header identifying the format.
+	Envelope bool
+	// FieldIDs makes the serializers identify struct fields by the id of their tag instead of by their position.
+	FieldIDs bool
+	// SchemaFingerprint makes the serializers write the fingerprint of the serialized type before the payload and
+	// check it against the target type on decoding.
+	SchemaFingerprint bool
+	// Checksum makes the serializers append a CRC32C trailer to their payloads and verify it on decoding.
+	Checksum bool
+	// ZeroCopy lets the serializers decode strings and byte slices as aliases of the payload instead of copies.
+	ZeroCopy bool
+}
+
+// UnexportedFieldPolicy decides how the binary serializers treat unexported struct fields.
+type UnexportedFieldPolicy uint8
+
+const (
+	// SkipUnexported leaves unexported fields out of the payload, like encoding/json does.
+	SkipUnexported UnexportedFieldPolicy = iota
+	// IncludeUnexported serializes unexported fields as well, reaching them through their address.
+	IncludeUnexported
+)
+
+// Option configures the binary serializers.
+type Option func(*Options)
+
+// NewOptions applies opts on top of the default settings.
+func NewOptions(opts ...Option) Options {
+	var o Options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// WithSkipUnsupported makes the serializers skip fields of unsupported kinds.
+func WithSkipUnsupported() Option {
+	return func(o *Options) {
+		o.SkipUnsupported = true
+	}
+}
+
+// WithUnexportedFields sets the policy applied to unexported struct fields.
+func WithUnexportedFields(policy UnexportedFieldPolicy) Option {
+	return func(o *Options) {
+		o.UnexportedFields = policy
+	}
+}
+
+// WithVarint makes the serializers write integers and lengths as varints.
+func WithVarint() Option {
+	return func(o *Options) {
+		o.Varint = true
+	}
+}
+
+// WithEnvelope makes the serializers wrap their payloads in a self-identifying envelope.
+func WithEnvelope() Option {
+	return func(o *Options) {
+		o.Envelope = true
+	}
+}
+
+// WithFieldIDs makes the serializers write struct fields along with their ids.
+func WithFieldIDs() Option {
+	return func(o *Options) {
+		o.FieldIDs = true
+	}
+}
+
+// WithSchemaFingerprint makes the serializers embed and verify schema fingerprints.
+func WithSchemaFingerprint() Option {
+	return func(o *Options) {
+		o.SchemaFingerprint = true
+	}
+}
+
+// WithChecksum makes the serializers append and verify CRC32C trailers.
+func WithChecksum() Option {
+	return func(o *Options) {
+		o.Checksum = true
+	}
+}
+
+// WithZeroCopy sets whether the serializers may decode strings and byte slices as aliases of the payload.
+func WithZeroCopy(enabled bool) Option {
+	return func(o *Options) {
+		o.ZeroCopy = enabled
+	}
+}
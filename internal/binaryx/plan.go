@@ -0,0 +1,168 @@
+package binaryx
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/bytesx"
+)
+
+// EncodeFunc writes a value of the type it was compiled for.
+type EncodeFunc func(bbw *bytesx.Writer, value reflect.Value)
+
+// DecodeFunc reads into an addressable value of the type it was compiled for.
+type DecodeFunc func(bbr *bytesx.Reader, value reflect.Value)
+
+// Plan holds the functions compiled for a type, so that the decisions depending on the type alone, such as its hooks,
+// kind and struct fields, are taken once rather than for every value.
+type Plan struct {
+	Encode EncodeFunc
+	Decode DecodeFunc
+}
+
+// Compiler compiles the plan of typ. The plans of the types typ is made of are taken from planOf; they may still be
+// under compilation when typ is recursive, so their functions must only be called once the compiler returns.
+type Compiler func(typ reflect.Type, planOf func(reflect.Type) *Plan) Plan
+
+type planKey struct {
+	typ  reflect.Type
+	opts Options
+}
+
+// PlanCache holds the plans compiled by a serializer, per type and options.
+type PlanCache struct {
+	// mu serializes compilations, so that partially compiled recursive plans are never published
+	mu    sync.Mutex
+	plans sync.Map // map[planKey]*Plan
+}
+
+var planCaches = struct {
+	sync.Mutex
+	caches []*PlanCache
+	// epoch counts the times the plans were dropped, telling Typed when its plan went stale
+	epoch atomic.Uint64
+}{}
+
+// NewPlanCache returns an empty PlanCache, dropped whenever a codec is registered.
+func NewPlanCache() *PlanCache {
+	c := &PlanCache{}
+
+	planCaches.Lock()
+	defer planCaches.Unlock()
+
+	planCaches.caches = append(planCaches.caches, c)
+	return c
+}
+
+// Load returns the plan of typ for o, when it was compiled already.
+func (c *PlanCache) Load(typ reflect.Type, o Options) (*Plan, bool) {
+	p, ok := c.plans.Load(planKey{typ: typ, opts: o})
+	if !ok {
+		return nil, false
+	}
+
+	return p.(*Plan), true
+}
+
+// Compile returns the plan of typ for o, compiling it with compile along with the plans it depends on unless another
+// goroutine did so first.
+func (c *PlanCache) Compile(typ reflect.Type, o Options, compile Compiler) *Plan {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	compiling := make(map[reflect.Type]*Plan)
+	var planOf func(reflect.Type) *Plan
+	planOf = func(typ reflect.Type) *Plan {
+		if p, ok := c.Load(typ, o); ok {
+			return p
+		}
+
+		if p, ok := compiling[typ]; ok {
+			return p
+		}
+
+		p := &Plan{}
+		compiling[typ] = p
+		*p = compile(typ, planOf)
+		return p
+	}
+
+	p := planOf(typ)
+	for typ, p := range compiling {
+		c.plans.Store(planKey{typ: typ, opts: o}, p)
+	}
+
+	return p
+}
+
+// FieldPlan is the plan of a struct field.
+type FieldPlan struct {
+	Field
+	// Plan is the plan of the field type, nil when the type is unsupported.
+	Plan *Plan
+	// WireType is the wire type of the field in the field id mode.
+	WireType WireType
+}
+
+// FieldPlans returns the plans of the fields of the struct type typ serialized with o, in wire order.
+func FieldPlans(typ reflect.Type, o Options, planOf func(reflect.Type) *Plan) ([]FieldPlan, error) {
+	fields, err := StructFields(typ)
+	if err != nil {
+		return nil, err
+	}
+
+	plans := make([]FieldPlan, 0, len(fields))
+	for _, sf := range fields {
+		if !sf.Exported && o.UnexportedFields == SkipUnexported {
+			continue
+		}
+
+		if sf.Unsupported != reflect.Invalid && o.SkipUnsupported {
+			continue
+		}
+
+		fp := FieldPlan{Field: sf, WireType: WireTypeOf(sf.Type, o.Varint)}
+		if sf.Unsupported == reflect.Invalid {
+			fp.Plan = planOf(sf.Type)
+		}
+
+		plans = append(plans, fp)
+	}
+
+	return plans, nil
+}
+
+// specialisedMaps holds the map types the serializers encode without reflection.
+var specialisedMaps = map[reflect.Type]bool{
+	reflect.TypeOf(map[int]int(nil)):                 true,
+	reflect.TypeOf(map[int64]int64(nil)):             true,
+	reflect.TypeOf(map[string]string(nil)):           true,
+	reflect.TypeOf(map[int]interface{}(nil)):         true,
+	reflect.TypeOf(map[int64]interface{}(nil)):       true,
+	reflect.TypeOf(map[string]interface{}(nil)):      true,
+	reflect.TypeOf(map[interface{}]interface{}(nil)): true,
+}
+
+// SpecialisedMap reports whether the serializers have a specialised path for the map type typ.
+func SpecialisedMap(typ reflect.Type) bool {
+	return specialisedMaps[typ]
+}
+
+// resetPlans drops the compiled plans, along with the per-type decisions they were compiled from, which may have
+// missed a codec registered since.
+func resetPlans() {
+	planCaches.Lock()
+	defer planCaches.Unlock()
+
+	primitiveSlices.Clear()
+	structFieldsCache.Clear()
+	fingerprintCache.Clear()
+	for _, c := range planCaches.caches {
+		c.mu.Lock()
+		c.plans.Clear()
+		c.mu.Unlock()
+	}
+
+	planCaches.epoch.Add(1)
+}
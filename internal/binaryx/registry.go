@@ -0,0 +1,109 @@
+package binaryx
+
+import (
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// NilTypeID identifies a nil interface value on the wire.
+const NilTypeID uint32 = 0
+
+// AnyType is the reflect.Type of interface{}.
+var AnyType = reflect.TypeOf((*interface{})(nil)).Elem()
+
+var registry = struct {
+	sync.RWMutex
+
+	byName map[string]reflect.Type
+	byType map[reflect.Type]uint32
+	byID   map[uint32]reflect.Type
+}{
+	byName: make(map[string]reflect.Type),
+	byType: make(map[reflect.Type]uint32),
+	byID:   make(map[uint32]reflect.Type),
+}
+
+func init() {
+	for _, value := range []interface{}{
+		false, "",
+		int(0), int8(0), int16(0), int32(0), int64(0),
+		uint(0), uint8(0), uint16(0), uint32(0), uint64(0),
+		float32(0), float64(0), complex64(0), complex128(0),
+		[]byte(nil), []string(nil), []int(nil), []int64(nil), []uint64(nil), []float64(nil),
+		[]interface{}(nil), map[string]interface{}(nil), map[interface{}]interface{}(nil),
+		time.Time{}, time.Duration(0),
+	} {
+		typ := reflect.TypeOf(value)
+		Register(typ.String(), typ)
+	}
+}
+
+// Register records typ under name so that interface values holding it can be serialized.
+//
+// Values are identified on the wire by a 32-bit hash of name, so the same name must be used by every
+// process exchanging payloads. Like gob.Register, it panics when name or typ are already registered
+// differently, or when typ cannot be serialized at all.
+func Register(name string, typ reflect.Type) {
+	if name == "" {
+		panic("binary: registering type with an empty name")
+	}
+
+	if typ == nil {
+		panic(fmt.Sprintf("binary: registering nil type for %q", name))
+	}
+
+	if kind := UnsupportedKind(typ); kind != reflect.Invalid {
+		panic(fmt.Sprintf("binary: registering type %s of unsupported kind %s", typ, kind))
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	id := h.Sum32()
+	if id == NilTypeID {
+		panic(fmt.Sprintf("binary: registering name %q hashing to the reserved nil type id", name))
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if registered, ok := registry.byName[name]; ok {
+		if registered != typ {
+			panic(fmt.Sprintf("binary: registering duplicate types for %q: %s != %s", name, registered, typ))
+		}
+
+		return
+	}
+
+	if _, ok := registry.byType[typ]; ok {
+		panic(fmt.Sprintf("binary: registering duplicate names for %s", typ))
+	}
+
+	if registered, ok := registry.byID[id]; ok {
+		panic(fmt.Sprintf("binary: type id of %q collides with the one of %s", name, registered))
+	}
+
+	registry.byName[name] = typ
+	registry.byType[typ] = id
+	registry.byID[id] = typ
+}
+
+// TypeID returns the wire identifier of the registered type typ.
+func TypeID(typ reflect.Type) (uint32, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	id, ok := registry.byType[typ]
+	return id, ok
+}
+
+// TypeByID returns the registered type identified by id.
+func TypeByID(id uint32) (reflect.Type, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	typ, ok := registry.byID[id]
+	return typ, ok
+}
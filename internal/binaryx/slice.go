@@ -0,0 +1,123 @@
+package binaryx
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/bytesx"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
+)
+
+// MaxZeroSizeLength is the largest length accepted for slices and maps of zero-size elements. Their elements may
+// take no byte in the payload, so their length cannot be checked against the bytes left to be read.
+const MaxZeroSizeLength = 1 << 16
+
+var (
+	byteType = reflect.TypeOf(byte(0))
+
+	primitiveSlices sync.Map // map[reflect.Type]string
+)
+
+// PrimitiveSlice returns the name of the unnamed slice type laid out like the slice type typ, such as "[]int64"
+// for both `type IDs []int64` and `[]Cents`, so that named types share the fast paths of their predeclared
+// counterparts. It returns "" when the elements are not primitives or have a codec or marshaler of their own.
+func PrimitiveSlice(typ reflect.Type) string {
+	elem := typ.Elem()
+	if typ.Name() == "" && elem.Name() != "" && elem.PkgPath() == "" && primitiveKind(elem.Kind()) {
+		// predeclared elements never have codecs or marshalers, but error is an interface
+		return typ.String()
+	}
+
+	if name, ok := primitiveSlices.Load(typ); ok {
+		return name.(string)
+	}
+
+	name := primitiveSlice(elem)
+	primitiveSlices.Store(typ, name)
+	return name
+}
+
+func primitiveSlice(elem reflect.Type) string {
+	if hasHooks(elem) {
+		return ""
+	}
+
+	switch kind := elem.Kind(); {
+	case primitiveKind(kind):
+		return "[]" + kind.String()
+	case kind == reflect.Slice:
+		// named byte elements cannot be converted from []byte, so only `type Blob []byte` is accepted
+		if elem.Elem() == byteType {
+			return "[][]uint8"
+		}
+
+		return ""
+	default:
+		return ""
+	}
+}
+
+// primitiveKind reports whether values of kind are written by the primitive slice fast paths.
+func primitiveKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	default:
+		return false
+	}
+}
+
+// SetSlice sets field to slice, which holds the elements decoded for the type named by PrimitiveSlice.
+func SetSlice(field, slice reflect.Value) {
+	typ := field.Type()
+	if slice.Type() == typ {
+		field.Set(slice)
+		return
+	}
+
+	if slice.Type().ConvertibleTo(typ) {
+		field.Set(slice.Convert(typ))
+		return
+	}
+
+	// named elements have to be converted one by one
+	elemType := typ.Elem()
+	converted := reflect.MakeSlice(typ, slice.Len(), slice.Len())
+	for i := 0; i < slice.Len(); i++ {
+		converted.Index(i).Set(slice.Index(i).Convert(elemType))
+	}
+
+	field.Set(converted)
+}
+
+// hasHooks reports whether typ is serialized through a registered codec or its marshalers.
+func hasHooks(typ reflect.Type) bool {
+	if _, ok := CodecOf(typ); ok {
+		return true
+	}
+
+	return MarshalerOf(typ) != NoMarshaler
+}
+
+// EnsureLength reports whether length elements of typ can be decoded from bbr: elements with a non-zero size take
+// at least one byte each in the payload, while there may be no more than MaxZeroSizeLength zero-size ones.
+// When they cannot, it records a decode error and returns false.
+func EnsureLength(bbr *bytesx.Reader, length int, size uintptr) bool {
+	if size > 0 {
+		return bbr.Ensure(length)
+	}
+
+	if length > MaxZeroSizeLength {
+		bbr.Fail(&models.DecodeError{
+			Offset: bbr.Yield(),
+			Reason: fmt.Sprintf("length %d of zero-size elements exceeds %d", length, MaxZeroSizeLength),
+		})
+		return false
+	}
+
+	return bbr.Err() == nil
+}
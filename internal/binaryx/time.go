@@ -0,0 +1,110 @@
+package binaryx
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/bytesx"
+)
+
+func init() {
+	RegisterCodec(reflect.TypeOf(time.Time{}), Codec{Encode: encodeTime, Decode: decodeTime})
+}
+
+// Zone markers of the times written by encodeTime.
+const (
+	timeZoneUTC byte = iota
+	// timeZoneLocation is followed by the location name, empty for unnamed fixed zones such as the ones time.Parse
+	// returns for numeric offsets.
+	timeZoneLocation
+)
+
+// encodeTime writes t as its Unix seconds (int64), nanoseconds (uint32), zone offset in seconds (int32), a zone
+// marker and, unless t is in UTC, its location name, length-prefixed. Monotonic clock readings are dropped.
+func encodeTime(bbw *bytesx.Writer, value reflect.Value) error {
+	t := value.Interface().(time.Time)
+
+	_, offset := t.Zone()
+	bbw.Write(bytesx.AddUint64(uint64(t.Unix())))
+	bbw.Write(bytesx.AddUint32(uint32(t.Nanosecond())))
+	bbw.Write(bytesx.AddUint32(uint32(int32(offset))))
+	if t.Location() == time.UTC {
+		bbw.Put(timeZoneUTC)
+		return nil
+	}
+
+	name := t.Location().String()
+	bbw.Put(timeZoneLocation)
+	bbw.Write(bytesx.AddUint32(uint32(len(name))))
+	bbw.Write([]byte(name))
+
+	return nil
+}
+
+// decodeTime reads the time written by encodeTime.
+//
+// The location is looked up by name and used when it yields the encoded offset at that instant;
+// otherwise a fixed zone with the encoded name and offset is used.
+func decodeTime(bbr *bytesx.Reader, value reflect.Value) error {
+	sec := int64(bbr.Uint64())
+	nsec := bbr.Uint32()
+	offset := int(int32(bbr.Uint32()))
+	zone := bbr.Next()
+
+	var name string
+	if zone == timeZoneLocation {
+		name = string(bbr.Read(int(bbr.Uint32())))
+	}
+
+	if bbr.Err() != nil {
+		return bbr.Err()
+	}
+
+	if nsec >= uint32(time.Second) {
+		return fmt.Errorf("nanoseconds out of range: %d", nsec)
+	}
+
+	t := time.Unix(sec, int64(nsec))
+	switch {
+	case zone == timeZoneUTC:
+		value.Set(reflect.ValueOf(t.UTC()))
+		return nil
+	case zone != timeZoneLocation:
+		return fmt.Errorf("unknown time zone marker: %d", zone)
+	case name == "":
+		value.Set(reflect.ValueOf(t.In(time.FixedZone("", offset))))
+		return nil
+	}
+
+	if loc := loadLocation(name); loc != nil {
+		if _, locOffset := t.In(loc).Zone(); locOffset == offset {
+			value.Set(reflect.ValueOf(t.In(loc)))
+			return nil
+		}
+	}
+
+	value.Set(reflect.ValueOf(t.In(time.FixedZone(name, offset))))
+	return nil
+}
+
+var locationCache sync.Map // map[string]*time.Location, nil when unknown
+
+func loadLocation(name string) *time.Location {
+	if cached, ok := locationCache.Load(name); ok {
+		return cached.(*time.Location)
+	}
+
+	var loc *time.Location
+	switch name {
+	case "Local":
+		loc = time.Local
+	default:
+		// unknown names, such as the ones of fixed zones, fall back to their offsets
+		loc, _ = time.LoadLocation(name)
+	}
+
+	locationCache.Store(name, loc)
+	return loc
+}
@@ -0,0 +1,113 @@
+package binaryx
+
+import (
+	"reflect"
+	"sync/atomic"
+	"unsafe"
+)
+
+// TypedCodec serializes the values of a type known in advance, such as the type parameter of serializer.Encode,
+// reached through a pointer to them.
+type TypedCodec struct {
+	// Encode returns the payload of the value ptr points to.
+	Encode func(ptr unsafe.Pointer) ([]byte, error)
+	// Decode reads data into the value ptr points to.
+	Decode func(data []byte, ptr unsafe.Pointer) error
+}
+
+// typedHooks hold the functions returning the TypedCodec of the binary serializers, registered by the packages
+// declaring them.
+var typedHooks []func(s interface{}, typ reflect.Type) (TypedCodec, bool)
+
+// RegisterTyped adds hook to the functions TypedCodecOf asks. It must be called from init functions.
+func RegisterTyped(hook func(s interface{}, typ reflect.Type) (TypedCodec, bool)) {
+	typedHooks = append(typedHooks, hook)
+}
+
+// TypedCodecOf returns the TypedCodec the serializer s has for typ, if any.
+func TypedCodecOf(s interface{}, typ reflect.Type) (TypedCodec, bool) {
+	for _, hook := range typedHooks {
+		if tc, ok := hook(s, typ); ok {
+			return tc, true
+		}
+	}
+
+	return TypedCodec{}, false
+}
+
+// Typed holds what a binary serializer resolves once for the values of a type: the type they are laid out as and
+// its plan. Pointers are followed once, like the serializers do with the values they are given.
+type Typed struct {
+	// Type is the type of the values.
+	Type reflect.Type
+
+	elem     reflect.Type
+	indirect bool
+
+	planOf func(reflect.Type) *Plan
+	plan   atomic.Pointer[typedPlan]
+}
+
+type typedPlan struct {
+	plan  *Plan
+	epoch uint64
+}
+
+// NewTyped returns the Typed of typ, whose plans are taken from planOf. It returns false for the types the
+// serializers only know how to handle through their dynamic type: interfaces and pointers to them or to pointers,
+// as well as unsupported types.
+func NewTyped(typ reflect.Type, planOf func(reflect.Type) *Plan) (*Typed, bool) {
+	t := &Typed{Type: typ, elem: typ, planOf: planOf}
+	if typ.Kind() == reflect.Ptr {
+		t.elem, t.indirect = typ.Elem(), true
+	}
+
+	switch t.elem.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		return nil, false
+	}
+
+	if UnsupportedKind(t.elem) != reflect.Invalid {
+		return nil, false
+	}
+
+	return t, true
+}
+
+// Plan returns the plan of the values, resolving it again once RegisterCodec dropped the plans.
+func (t *Typed) Plan() *Plan {
+	epoch := planCaches.epoch.Load()
+	if p := t.plan.Load(); p != nil && p.epoch == epoch {
+		return p.plan
+	}
+
+	plan := t.planOf(t.elem)
+	t.plan.Store(&typedPlan{plan: plan, epoch: epoch})
+	return plan
+}
+
+// Value returns the value ptr points to, as the serializers lay it out. It returns false for nil pointers, which
+// have an empty payload.
+func (t *Typed) Value(ptr unsafe.Pointer) (reflect.Value, bool) {
+	if t.indirect {
+		if ptr = *(*unsafe.Pointer)(ptr); ptr == nil {
+			return reflect.Value{}, false
+		}
+	}
+
+	return reflect.NewAt(t.elem, ptr).Elem(), true
+}
+
+// Target returns the addressable value decoded into for the value ptr points to, allocating it for nil pointers.
+func (t *Typed) Target(ptr unsafe.Pointer) reflect.Value {
+	if t.indirect {
+		target := (*unsafe.Pointer)(ptr)
+		if *target == nil {
+			*target = reflect.New(t.elem).UnsafePointer()
+		}
+
+		ptr = *target
+	}
+
+	return reflect.NewAt(t.elem, ptr).Elem()
+}
@@ -0,0 +1,81 @@
+package bytesx
+
+import (
+	"math/bits"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// minClassShift is the log2 of the smallest buffer size class.
+	minClassShift = 6
+	// classCount is the number of buffer size classes, from 64 B to 1 MiB.
+	classCount = 15
+	// maxPooledCap is the capacity above which buffers are left to the garbage collector.
+	maxPooledCap = 1 << (minClassShift + classCount - 1)
+)
+
+// Pool recycles Writers along with their buffers. Buffers are sorted into power of two size classes, so that small
+// payloads do not hold on to large buffers while large payloads do not grow from small ones.
+type Pool struct {
+	classes [classCount]sync.Pool
+	// appenders holds the Writers handed out by GetAppender, without their buffer
+	appenders sync.Pool
+
+	// hint is the length of the last payload written, which sizes the buffer of the next one
+	hint atomic.Int64
+}
+
+func NewPool() *Pool {
+	return &Pool{}
+}
+
+// Get returns an empty Writer whose buffer can hold the last payload released.
+func (p *Pool) Get() *Writer {
+	class := classOf(int(p.hint.Load()))
+	if bbw, ok := p.classes[class].Get().(*Writer); ok {
+		bbw.Reset()
+		return bbw
+	}
+
+	return NewAppendWriter(make([]byte, 0, 1<<(class+minClassShift)))
+}
+
+// Put releases bbw to the pool. Neither bbw nor the bytes it wrote may be used afterwards.
+func (p *Pool) Put(bbw *Writer) {
+	p.hint.Store(int64(bbw.Len()))
+
+	capacity := cap(bbw.data)
+	if capacity < 1<<minClassShift || capacity > maxPooledCap {
+		return
+	}
+
+	// buffers go to the largest class they can hold, as Get counts on their size
+	p.classes[bits.Len(uint(capacity))-1-minClassShift].Put(bbw)
+}
+
+// GetAppender returns a Writer appending to dst, in the manner of NewAppendWriter.
+func (p *Pool) GetAppender(dst []byte) *Writer {
+	bbw, ok := p.appenders.Get().(*Writer)
+	if !ok {
+		return NewAppendWriter(dst)
+	}
+
+	bbw.appendTo(dst)
+	return bbw
+}
+
+// PutAppender releases a Writer returned by GetAppender to the pool, leaving its buffer to the caller.
+func (p *Pool) PutAppender(bbw *Writer) {
+	bbw.data = nil
+	p.appenders.Put(bbw)
+}
+
+// classOf returns the smallest class holding size bytes, or the largest class.
+func classOf(size int) int {
+	if size <= 1<<minClassShift {
+		return 0
+	}
+
+	return min(bits.Len(uint(size-1))-minClassShift, classCount-1)
+}
@@ -1,8 +1,22 @@
 package bytesx
 
+import (
+	"encoding/binary"
+	"math"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
+)
+
+// Reader reads from a byte buffer keeping track of a cursor.
+//
+// Every read is checked against the remaining input. The first out-of-bounds read records a
+// *models.DecodeError; from then on all reads return zero values without moving the cursor,
+// so decoders can check Err once per field instead of after every single read.
 type Reader struct {
 	data   []byte
 	cursor int
+
+	err error
 }
 
 func NewReader(data []byte) *Reader {
@@ -12,20 +26,179 @@ func NewReader(data []byte) *Reader {
 }
 
 func (bbr *Reader) Next() byte {
+	if !bbr.Ensure(1) {
+		return 0
+	}
+
 	bbr.cursor++
 	return bbr.data[bbr.cursor-1]
 }
 
 func (bbr *Reader) Read(n int) []byte {
+	if !bbr.Ensure(n) {
+		return nil
+	}
+
 	bbr.cursor += n
 	return bbr.data[bbr.cursor-n : bbr.cursor]
 }
 
+func (bbr *Reader) Uint16() uint16 {
+	bs := bbr.Read(2)
+	if bs == nil {
+		return 0
+	}
+
+	return Uint16(bs)
+}
+
+func (bbr *Reader) Uint32() uint32 {
+	bs := bbr.Read(4)
+	if bs == nil {
+		return 0
+	}
+
+	return Uint32(bs)
+}
+
+func (bbr *Reader) Uint64() uint64 {
+	bs := bbr.Read(8)
+	if bs == nil {
+		return 0
+	}
+
+	return Uint64(bs)
+}
+
+// Uvarint reads an unsigned LEB128 varint.
+func (bbr *Reader) Uvarint() uint64 {
+	if bbr.err != nil {
+		return 0
+	}
+
+	v, n := binary.Uvarint(bbr.data[bbr.cursor:])
+	if n <= 0 {
+		bbr.failVarint(n)
+		return 0
+	}
+
+	bbr.cursor += n
+	return v
+}
+
+// Varint reads a zigzag encoded signed LEB128 varint.
+func (bbr *Reader) Varint() int64 {
+	if bbr.err != nil {
+		return 0
+	}
+
+	v, n := binary.Varint(bbr.data[bbr.cursor:])
+	if n <= 0 {
+		bbr.failVarint(n)
+		return 0
+	}
+
+	bbr.cursor += n
+	return v
+}
+
+// UvarintLength reads a length written as an unsigned varint.
+// Like fixed-width lengths, it must fit in 32 bits.
+func (bbr *Reader) UvarintLength() int {
+	offset := bbr.cursor
+	v := bbr.Uvarint()
+	if v > math.MaxUint32 {
+		bbr.err = &models.DecodeError{Offset: offset, Reason: "length overflows 32 bits"}
+		return 0
+	}
+
+	return int(v)
+}
+
+// failVarint records the decode error matching the n returned by binary.Uvarint and binary.Varint.
+func (bbr *Reader) failVarint(n int) {
+	if n == 0 {
+		// the payload ends in the middle of the varint
+		remaining := len(bbr.data) - bbr.cursor
+		bbr.err = &models.DecodeError{Offset: bbr.cursor, Expected: remaining + 1, Remaining: remaining}
+		return
+	}
+
+	bbr.err = &models.DecodeError{Offset: bbr.cursor, Reason: "varint overflows 64 bits"}
+}
+
+// Ensure reports whether at least n bytes are left to be read.
+// When they are not, it records a decode error and returns false.
+func (bbr *Reader) Ensure(n int) bool {
+	if bbr.err != nil {
+		return false
+	}
+
+	if n < 0 || n > len(bbr.data)-bbr.cursor {
+		bbr.err = &models.DecodeError{
+			Offset:    bbr.cursor,
+			Expected:  n,
+			Remaining: len(bbr.data) - bbr.cursor,
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// TrimTrailer removes the last n bytes from the data left to be read and returns them.
+func (bbr *Reader) TrimTrailer(n int) []byte {
+	if !bbr.Ensure(n) {
+		return nil
+	}
+
+	end := len(bbr.data) - n
+	trailer := bbr.data[end:]
+	bbr.data = bbr.data[:end]
+	return trailer
+}
+
+// Err returns the first error found while reading, if any.
+func (bbr *Reader) Err() error {
+	return bbr.err
+}
+
+// Fail records err as the reader's error unless one was already recorded.
+func (bbr *Reader) Fail(err error) {
+	if bbr.err == nil {
+		bbr.err = err
+	}
+}
+
+// AnnotateField prefixes the recorded decode error's field path with name.
+// typ is the name of the type being decoded and is only kept for the innermost field.
+func (bbr *Reader) AnnotateField(name, typ string) {
+	switch err := bbr.err.(type) {
+	case *models.DecodeError:
+		err.PrependField(name)
+		if err.Type == "" {
+			err.Type = typ
+		}
+	case interface{ PrependField(string) }:
+		err.PrependField(name)
+	}
+}
+
+// Len returns the number of bytes left to be read.
+func (bbr *Reader) Len() int {
+	return len(bbr.data) - bbr.cursor
+}
+
 func (bbr *Reader) Yield() int {
 	return bbr.cursor
 }
 
 func (bbr *Reader) Skip(n int) {
+	if !bbr.Ensure(n) {
+		return
+	}
+
 	bbr.cursor += n
 }
 
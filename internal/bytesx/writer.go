@@ -1,10 +1,20 @@
 package bytesx
 
+import (
+	"encoding/binary"
+)
+
+// Writer appends to a growing byte buffer.
+//
+// Encoders record the first error they find with Fail; writes keep succeeding afterwards so that
+// the error only needs to be checked once per field.
 type Writer struct {
 	data   []byte
 	cursor int
 
 	freeCap int // cap(data) - len(data)
+
+	err error
 }
 
 func NewWriter(data []byte) *Writer {
@@ -24,6 +34,33 @@ func NewWriter(data []byte) *Writer {
 	return bbw
 }
 
+// NewAppendWriter returns a Writer appending to dst, keeping its content. Writes go straight into the spare
+// capacity of dst until it runs out.
+func NewAppendWriter(dst []byte) *Writer {
+	bbw := &Writer{}
+	bbw.appendTo(dst)
+	return bbw
+}
+
+func (bbw *Writer) appendTo(dst []byte) {
+	if cap(dst) == 0 {
+		dst = make([]byte, 0, 1<<6)
+	}
+
+	bbw.data = dst[:cap(dst)]
+	bbw.cursor = len(dst)
+	bbw.freeCap = cap(dst) - len(dst)
+	bbw.err = nil
+}
+
+// Reset rewinds bbw to the start of its buffer and clears its error, so that the buffer is reused by the next writes.
+func (bbw *Writer) Reset() {
+	bbw.data = bbw.data[:cap(bbw.data)]
+	bbw.cursor = 0
+	bbw.freeCap = cap(bbw.data)
+	bbw.err = nil
+}
+
 func (bbw *Writer) Put(b byte) {
 	if 1 >= bbw.freeCap {
 		newDataCap := cap(bbw.data) << 1
@@ -58,6 +95,48 @@ func (bbw *Writer) Write(bs []byte) {
 	bbw.freeCap -= bsLen
 }
 
+// PutUvarint writes v as an unsigned LEB128 varint.
+func (bbw *Writer) PutUvarint(v uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	bbw.Write(buf[:binary.PutUvarint(buf[:], v)])
+}
+
+// PutVarint writes v as a zigzag encoded signed LEB128 varint.
+func (bbw *Writer) PutVarint(v int64) {
+	var buf [binary.MaxVarintLen64]byte
+	bbw.Write(buf[:binary.PutVarint(buf[:], v)])
+}
+
+// Len returns the number of bytes written so far.
+func (bbw *Writer) Len() int {
+	return bbw.cursor
+}
+
+// PatchUint32 overwrites the four bytes written at offset with v, filling in a placeholder whose value was only
+// known once the bytes following it were written.
+func (bbw *Writer) PatchUint32(offset int, v uint32) {
+	PutUint32(bbw.data[offset:offset+4], v)
+}
+
 func (bbw *Writer) Bytes() []byte {
 	return bbw.data[:bbw.cursor]
 }
+
+// Err returns the first error recorded while writing, if any.
+func (bbw *Writer) Err() error {
+	return bbw.err
+}
+
+// Fail records err as the writer's error unless one was already recorded.
+func (bbw *Writer) Fail(err error) {
+	if bbw.err == nil {
+		bbw.err = err
+	}
+}
+
+// AnnotateField prefixes the recorded error's field path with name.
+func (bbw *Writer) AnnotateField(name string) {
+	if err, ok := bbw.err.(interface{ PrependField(string) }); ok {
+		err.PrependField(name)
+	}
+}
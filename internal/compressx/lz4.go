@@ -0,0 +1,222 @@
+// Package compressx implements the block codecs used by the compression decorator that the standard library does
+// not provide.
+package compressx
+
+import (
+	"encoding/binary"
+	"errors"
+	"slices"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
+)
+
+// ErrCorrupt reports a block that does not decode to the size it announces.
+var ErrCorrupt = errors.New("corrupt lz4 block")
+
+// The LZ4 block format is a sequence of literal runs, each one followed by a match copying bytes already decoded.
+// Every sequence starts with a token holding the literal length in its upper four bits and the match length, minus
+// minMatch, in its lower ones; lengths of 15 or more continue in the following bytes. The last sequence only holds
+// literals.
+const (
+	minMatch = 4
+	// the last match must start at least mfLimit bytes before the end of the block
+	mfLimit = 12
+	// the last lastLiterals bytes of the block are always literals
+	lastLiterals = 5
+	maxOffset    = 1<<16 - 1
+
+	hashLog = 14
+
+	// each byte of the block expands to at most 255 bytes
+	maxRatio = 255
+)
+
+// AppendLZ4 appends to dst src compressed into an LZ4 block, prefixed with the uvarint length of src.
+func AppendLZ4(dst, src []byte) []byte {
+	dst = slices.Grow(dst, binary.MaxVarintLen64+len(src)+len(src)/255+16)
+	dst = binary.AppendUvarint(dst, uint64(len(src)))
+	return compressBlock(dst, src)
+}
+
+// DecodeLZ4 decompresses a block written by AppendLZ4. Blocks announcing more than max bytes fail with
+// models.ErrDecompressedTooLarge before anything is allocated.
+func DecodeLZ4(src []byte, max int) ([]byte, error) {
+	size, n := binary.Uvarint(src)
+	if n <= 0 {
+		return nil, ErrCorrupt
+	}
+
+	if size > uint64(max) {
+		return nil, models.ErrDecompressedTooLarge
+	}
+
+	block := src[n:]
+	if size > uint64(len(block))*maxRatio {
+		return nil, ErrCorrupt
+	}
+
+	return decompressBlock(block, int(size))
+}
+
+func compressBlock(dst, src []byte) []byte {
+	if len(src) <= mfLimit {
+		return appendLastLiterals(dst, src)
+	}
+
+	// table holds the position + 1 of the last sequence of four bytes seen for each hash
+	var table [1 << hashLog]int32
+
+	anchor := 0
+	limit := len(src) - mfLimit
+	maxEnd := len(src) - lastLiterals
+	for i := 0; i < limit; {
+		seq := binary.LittleEndian.Uint32(src[i:])
+		h := hash(seq)
+		ref := int(table[h]) - 1
+		table[h] = int32(i + 1)
+
+		if ref < 0 || i-ref > maxOffset || binary.LittleEndian.Uint32(src[ref:]) != seq {
+			// skip faster through incompressible input
+			i += 1 + (i-anchor)>>6
+			continue
+		}
+
+		for i > anchor && ref > 0 && src[i-1] == src[ref-1] {
+			i--
+			ref--
+		}
+
+		end := i + minMatch
+		for end < maxEnd && src[end] == src[ref+end-i] {
+			end++
+		}
+
+		dst = appendSequence(dst, src[anchor:i], i-ref, end-i)
+		i = end
+		anchor = end
+	}
+
+	return appendLastLiterals(dst, src[anchor:])
+}
+
+func hash(seq uint32) uint32 {
+	return seq * 2654435761 >> (32 - hashLog)
+}
+
+func appendSequence(dst, literals []byte, offset, matchLen int) []byte {
+	litLen, extra := len(literals), matchLen-minMatch
+	dst = append(dst, byte(min(litLen, 15))<<4|byte(min(extra, 15)))
+	if litLen >= 15 {
+		dst = appendLength(dst, litLen-15)
+	}
+
+	dst = append(dst, literals...)
+	dst = append(dst, byte(offset), byte(offset>>8))
+	if extra >= 15 {
+		dst = appendLength(dst, extra-15)
+	}
+
+	return dst
+}
+
+func appendLastLiterals(dst, literals []byte) []byte {
+	litLen := len(literals)
+	dst = append(dst, byte(min(litLen, 15))<<4)
+	if litLen >= 15 {
+		dst = appendLength(dst, litLen-15)
+	}
+
+	return append(dst, literals...)
+}
+
+func appendLength(dst []byte, n int) []byte {
+	for ; n >= 255; n -= 255 {
+		dst = append(dst, 255)
+	}
+
+	return append(dst, byte(n))
+}
+
+func decompressBlock(src []byte, size int) ([]byte, error) {
+	dst := make([]byte, 0, size)
+	for i := 0; i < len(src); {
+		token := src[i]
+		i++
+
+		litLen := int(token >> 4)
+		if litLen == 15 {
+			n, next, ok := readLength(src, i)
+			if !ok {
+				return nil, ErrCorrupt
+			}
+
+			litLen, i = litLen+n, next
+		}
+
+		if litLen > len(src)-i || litLen > size-len(dst) {
+			return nil, ErrCorrupt
+		}
+
+		dst = append(dst, src[i:i+litLen]...)
+		i += litLen
+		if i == len(src) {
+			break
+		}
+
+		if len(src)-i < 2 {
+			return nil, ErrCorrupt
+		}
+
+		offset := int(src[i]) | int(src[i+1])<<8
+		i += 2
+		if offset == 0 || offset > len(dst) {
+			return nil, ErrCorrupt
+		}
+
+		matchLen := int(token & 15)
+		if matchLen == 15 {
+			n, next, ok := readLength(src, i)
+			if !ok {
+				return nil, ErrCorrupt
+			}
+
+			matchLen, i = matchLen+n, next
+		}
+
+		matchLen += minMatch
+		if matchLen > size-len(dst) {
+			return nil, ErrCorrupt
+		}
+
+		start := len(dst) - offset
+		if offset >= matchLen {
+			dst = append(dst, dst[start:start+matchLen]...)
+			continue
+		}
+
+		// the match overlaps the bytes it produces, repeating the last offset bytes
+		for j := 0; j < matchLen; j++ {
+			dst = append(dst, dst[start+j])
+		}
+	}
+
+	if len(dst) != size {
+		return nil, ErrCorrupt
+	}
+
+	return dst, nil
+}
+
+func readLength(src []byte, i int) (int, int, bool) {
+	n := 0
+	for i < len(src) {
+		b := src[i]
+		i++
+		n += int(b)
+		if b != 255 {
+			return n, i, true
+		}
+	}
+
+	return 0, i, false
+}
@@ -0,0 +1,92 @@
+// Package gentestmodels holds the types the tests of serializergen generate methods for.
+package gentestmodels
+
+import "time"
+
+//go:generate go run ../../cmd/serializergen -type=Order,OrderLine
+
+type (
+	Status string
+
+	Price float64
+
+	Tags []string
+
+	Order struct {
+		ID        uint64 `binary:",order=1"`
+		Revision  int32  `binary:",order=0"`
+		Customer  string
+		Status    Status
+		Paid      bool
+		Total     Price
+		Discount  float32
+		Offset    complex128
+		Priority  int8
+		Region    uint16
+		Tags      Tags
+		Notes     []string
+		Signature []byte
+		Checksum  [4]byte
+		Grid      [2][2]int16
+		Lines     []OrderLine
+		Gift      *OrderLine
+		Coupon    *string
+		ByRef     map[string]OrderLine
+		Counts    map[int]int
+		Extra     map[string]interface{}
+		Meta      interface{}
+		CreatedAt time.Time
+		TTL       time.Duration
+		Cache     []byte `binary:"-"`
+		internal  int
+	}
+
+	OrderLine struct {
+		SKU    string
+		Qty    uint32
+		Chunks [][]byte
+	}
+)
+
+// Reflected mirrors of the types above, without generated methods.
+type (
+	ReflectedOrder struct {
+		ID        uint64 `binary:",order=1"`
+		Revision  int32  `binary:",order=0"`
+		Customer  string
+		Status    Status
+		Paid      bool
+		Total     Price
+		Discount  float32
+		Offset    complex128
+		Priority  int8
+		Region    uint16
+		Tags      Tags
+		Notes     []string
+		Signature []byte
+		Checksum  [4]byte
+		Grid      [2][2]int16
+		Lines     []ReflectedOrderLine
+		Gift      *ReflectedOrderLine
+		Coupon    *string
+		ByRef     map[string]ReflectedOrderLine
+		Counts    map[int]int
+		Extra     map[string]interface{}
+		Meta      interface{}
+		CreatedAt time.Time
+		TTL       time.Duration
+		Cache     []byte `binary:"-"`
+		internal  int
+	}
+
+	ReflectedOrderLine struct {
+		SKU    string
+		Qty    uint32
+		Chunks [][]byte
+	}
+)
+
+// SetInternal sets the unexported field of o, which the serializers leave out with their default options.
+func (o *Order) SetInternal(v int) {
+	o.internal = v
+}
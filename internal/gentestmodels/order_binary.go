@@ -0,0 +1,343 @@
+// Code generated by serializergen; DO NOT EDIT.
+
+package gentestmodels
+
+import (
+	"math"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer"
+)
+
+// MarshalBinaryTo writes x in the layout of serializer.BinarySerializer.
+func (x *Order) MarshalBinaryTo(w *serializer.Writer) error {
+	w.WriteUint32(uint32(x.Revision))
+	w.WriteUint64(x.ID)
+	w.WriteString(x.Customer)
+	w.WriteString(string(x.Status))
+	w.WriteBool(x.Paid)
+	w.WriteUint64(math.Float64bits(float64(x.Total)))
+	w.WriteUint32(math.Float32bits(x.Discount))
+	w.WriteUint64(math.Float64bits(real(x.Offset)))
+	w.WriteUint64(math.Float64bits(imag(x.Offset)))
+	w.WriteByte(uint8(x.Priority))
+	w.WriteUint16(x.Region)
+	w.WriteUint32(uint32(len(x.Tags)))
+	for i1 := range x.Tags {
+		w.WriteString(x.Tags[i1])
+	}
+	w.WriteUint32(uint32(len(x.Notes)))
+	for i2 := range x.Notes {
+		w.WriteString(x.Notes[i2])
+	}
+	w.WriteBytes(x.Signature)
+	w.Write(x.Checksum[:])
+	for i3 := range x.Grid {
+		for i4 := range x.Grid[i3] {
+			w.WriteUint16(uint16(x.Grid[i3][i4]))
+		}
+	}
+	w.WriteUint32(uint32(len(x.Lines)))
+	for i5 := range x.Lines {
+		if err := x.Lines[i5].MarshalBinaryTo(w); err != nil {
+			return err
+		}
+	}
+	if x.Gift == nil {
+		w.WriteBool(true)
+	} else {
+		w.WriteBool(false)
+		if err := x.Gift.MarshalBinaryTo(w); err != nil {
+			return err
+		}
+	}
+	if x.Coupon == nil {
+		w.WriteBool(true)
+	} else {
+		w.WriteBool(false)
+		w.WriteString(*x.Coupon)
+	}
+	w.WriteUint32(uint32(len(x.ByRef)))
+	for k6, v7 := range x.ByRef {
+		w.WriteString(k6)
+		if err := v7.MarshalBinaryTo(w); err != nil {
+			return err
+		}
+	}
+	w.WriteUint32(uint32(len(x.Counts)))
+	for k8, v9 := range x.Counts {
+		w.WriteUint64(uint64(k8))
+		w.WriteUint64(uint64(v9))
+	}
+	w.WriteUint32(uint32(len(x.Extra)))
+	for k10, v11 := range x.Extra {
+		w.WriteString(k10)
+		if err := w.WriteValue(&v11); err != nil {
+			return err
+		}
+	}
+	if err := w.WriteValue(&x.Meta); err != nil {
+		return err
+	}
+	if err := w.WriteValue(&x.CreatedAt); err != nil {
+		return err
+	}
+	if err := w.WriteValue(&x.TTL); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UnmarshalBinaryFrom reads x from the layout of serializer.BinarySerializer.
+func (x *Order) UnmarshalBinaryFrom(r *serializer.Reader) error {
+	v1, err := r.ReadUint32()
+	if err != nil {
+		return err
+	}
+	x.Revision = int32(v1)
+	v2, err := r.ReadUint64()
+	if err != nil {
+		return err
+	}
+	x.ID = v2
+	v3, err := r.ReadString()
+	if err != nil {
+		return err
+	}
+	x.Customer = v3
+	v4, err := r.ReadString()
+	if err != nil {
+		return err
+	}
+	x.Status = Status(v4)
+	v5, err := r.ReadBool()
+	if err != nil {
+		return err
+	}
+	x.Paid = v5
+	v6, err := r.ReadUint64()
+	if err != nil {
+		return err
+	}
+	x.Total = Price(math.Float64frombits(v6))
+	v7, err := r.ReadUint32()
+	if err != nil {
+		return err
+	}
+	x.Discount = math.Float32frombits(v7)
+	v8, err := r.ReadUint64()
+	if err != nil {
+		return err
+	}
+	v9, err := r.ReadUint64()
+	if err != nil {
+		return err
+	}
+	x.Offset = complex(math.Float64frombits(v8), math.Float64frombits(v9))
+	v10, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+	x.Priority = int8(v10)
+	v11, err := r.ReadUint16()
+	if err != nil {
+		return err
+	}
+	x.Region = v11
+	n12, err := r.ReadLength(1)
+	if err != nil {
+		return err
+	}
+	if n12 > 0 {
+		x.Tags = make(Tags, n12)
+		for i13 := range x.Tags {
+			v14, err := r.ReadString()
+			if err != nil {
+				return err
+			}
+			x.Tags[i13] = v14
+		}
+	}
+	n15, err := r.ReadLength(1)
+	if err != nil {
+		return err
+	}
+	if n15 > 0 {
+		x.Notes = make([]string, n15)
+		for i16 := range x.Notes {
+			v17, err := r.ReadString()
+			if err != nil {
+				return err
+			}
+			x.Notes[i16] = v17
+		}
+	}
+	v18, err := r.ReadBytes()
+	if err != nil {
+		return err
+	}
+	if len(v18) > 0 {
+		x.Signature = v18
+	}
+	v19, err := r.Next(4)
+	if err != nil {
+		return err
+	}
+	copy(x.Checksum[:], v19)
+	for i20 := range x.Grid {
+		for i21 := range x.Grid[i20] {
+			v22, err := r.ReadUint16()
+			if err != nil {
+				return err
+			}
+			x.Grid[i20][i21] = int16(v22)
+		}
+	}
+	n23, err := r.ReadLength(1)
+	if err != nil {
+		return err
+	}
+	if n23 > 0 {
+		x.Lines = make([]OrderLine, n23)
+		for i24 := range x.Lines {
+			if err := x.Lines[i24].UnmarshalBinaryFrom(r); err != nil {
+				return err
+			}
+		}
+	}
+	isNil25, err := r.ReadBool()
+	if err != nil {
+		return err
+	}
+	if !isNil25 {
+		x.Gift = new(OrderLine)
+		if err := x.Gift.UnmarshalBinaryFrom(r); err != nil {
+			return err
+		}
+	}
+	isNil26, err := r.ReadBool()
+	if err != nil {
+		return err
+	}
+	if !isNil26 {
+		x.Coupon = new(string)
+		v27, err := r.ReadString()
+		if err != nil {
+			return err
+		}
+		*x.Coupon = v27
+	}
+	n28, err := r.ReadLength(1)
+	if err != nil {
+		return err
+	}
+	if n28 > 0 {
+		x.ByRef = make(map[string]OrderLine, n28)
+		for i29 := 0; i29 < n28; i29++ {
+			var k30 string
+			v31, err := r.ReadString()
+			if err != nil {
+				return err
+			}
+			k30 = v31
+			var v32 OrderLine
+			if err := v32.UnmarshalBinaryFrom(r); err != nil {
+				return err
+			}
+			x.ByRef[k30] = v32
+		}
+	}
+	n33, err := r.ReadLength(1)
+	if err != nil {
+		return err
+	}
+	if n33 > 0 {
+		x.Counts = make(map[int]int, n33)
+		for i34 := 0; i34 < n33; i34++ {
+			var k35 int
+			v36, err := r.ReadUint64()
+			if err != nil {
+				return err
+			}
+			k35 = int(v36)
+			var v37 int
+			v38, err := r.ReadUint64()
+			if err != nil {
+				return err
+			}
+			v37 = int(v38)
+			x.Counts[k35] = v37
+		}
+	}
+	n39, err := r.ReadLength(1)
+	if err != nil {
+		return err
+	}
+	if n39 > 0 {
+		x.Extra = make(map[string]interface{}, n39)
+		for i40 := 0; i40 < n39; i40++ {
+			var k41 string
+			v42, err := r.ReadString()
+			if err != nil {
+				return err
+			}
+			k41 = v42
+			var v43 interface{}
+			if err := r.ReadValue(&v43); err != nil {
+				return err
+			}
+			x.Extra[k41] = v43
+		}
+	}
+	if err := r.ReadValue(&x.Meta); err != nil {
+		return err
+	}
+	if err := r.ReadValue(&x.CreatedAt); err != nil {
+		return err
+	}
+	if err := r.ReadValue(&x.TTL); err != nil {
+		return err
+	}
+	return nil
+}
+
+// MarshalBinaryTo writes x in the layout of serializer.BinarySerializer.
+func (x *OrderLine) MarshalBinaryTo(w *serializer.Writer) error {
+	w.WriteString(x.SKU)
+	w.WriteUint32(x.Qty)
+	w.WriteUint32(uint32(len(x.Chunks)))
+	for i1 := range x.Chunks {
+		w.WriteBytes(x.Chunks[i1])
+	}
+	return nil
+}
+
+// UnmarshalBinaryFrom reads x from the layout of serializer.BinarySerializer.
+func (x *OrderLine) UnmarshalBinaryFrom(r *serializer.Reader) error {
+	v1, err := r.ReadString()
+	if err != nil {
+		return err
+	}
+	x.SKU = v1
+	v2, err := r.ReadUint32()
+	if err != nil {
+		return err
+	}
+	x.Qty = v2
+	n3, err := r.ReadLength(1)
+	if err != nil {
+		return err
+	}
+	if n3 > 0 {
+		x.Chunks = make([][]byte, n3)
+		for i4 := range x.Chunks {
+			v5, err := r.ReadBytes()
+			if err != nil {
+				return err
+			}
+			if len(v5) > 0 {
+				x.Chunks[i4] = v5
+			}
+		}
+	}
+	return nil
+}
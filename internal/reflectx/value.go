@@ -55,6 +55,12 @@ func (v Value) StringToBytes() []byte {
 	return unsafe.Slice(unsafe.StringData(str), len(str))
 }
 
+// Exported returns a settable alias of v, which must be addressable, lifting the read-only flag
+// that reflect puts on values reached through unexported struct fields.
+func Exported(v reflect.Value) reflect.Value {
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
+
 func Bytefy(str string) []byte {
 	return unsafe.Slice(unsafe.StringData(str), len(str))
 }
@@ -1,5 +1,14 @@
 package testmodels
 
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/netip"
+	"time"
+	"unsafe"
+)
+
 type (
 	IntSliceTestData struct {
 		IntList []int `json:"int_list,omitempty"`
@@ -273,4 +282,300 @@ type (
 		SliceTestData  SliceTestData `json:"slice_test_data,omitempty"`
 		MapTestData    MapTestData   `json:"map_test_data,omitempty"`
 	}
+
+	UnsupportedFieldsTestData struct {
+		Name     string         `json:"name,omitempty"`
+		Done     chan struct{}  `json:"-"`
+		Callback func() error   `json:"-"`
+		Handle   uintptr        `json:"-"`
+		Ptr      unsafe.Pointer `json:"-"`
+		Count    int64          `json:"count,omitempty"`
+	}
+
+	NestedUnsupportedFieldsTestData struct {
+		Id    string                      `json:"id,omitempty"`
+		Items []UnsupportedFieldsTestData `json:"items,omitempty"`
+	}
+
+	TaggedStructTestData struct {
+		Name  string            `json:"name,omitempty" binary:",order=2"`
+		Cache map[string]string `json:"-" binary:"-"`
+		Id    int64             `json:"id,omitempty" binary:"identifier,order=0"`
+		Note  string            `json:"note,omitempty"`
+		Count uint32            `json:"count,omitempty" binary:",order=1"`
+		Done  chan struct{}     `json:"-" binary:"-"`
+	}
+
+	// TaggedStructWireEquivalentTestData declares the fields of TaggedStructTestData in their wire order.
+	TaggedStructWireEquivalentTestData struct {
+		Id    int64  `json:"id,omitempty"`
+		Count uint32 `json:"count,omitempty"`
+		Name  string `json:"name,omitempty"`
+		Note  string `json:"note,omitempty"`
+	}
+
+	InvalidTagTestData struct {
+		Name string `json:"name,omitempty" binary:",order=first"`
+	}
+
+	UnexportedFieldsTestData struct {
+		Name      string    `json:"name,omitempty"`
+		CreatedAt time.Time `json:"created_at,omitempty"`
+
+		secret  string
+		counter int64
+		tags    []string
+		sub     *SubItem
+	}
+
+	InterfaceTestData struct {
+		Id       string                      `json:"id,omitempty"`
+		Payload  interface{}                 `json:"payload,omitempty"`
+		Label    fmt.Stringer                `json:"label,omitempty"`
+		Items    []interface{}               `json:"items,omitempty"`
+		Metadata map[string]interface{}      `json:"metadata,omitempty"`
+		Indexed  map[int]interface{}         `json:"indexed,omitempty"`
+		Dynamic  map[interface{}]interface{} `json:"dynamic,omitempty"`
+	}
+
+	Money struct {
+		Amount   int64  `json:"amount,omitempty"`
+		Currency string `json:"currency,omitempty"`
+	}
+
+	// Cents implements encoding.BinaryMarshaler over its unexported value.
+	Cents struct {
+		value int64
+	}
+
+	// Code implements encoding.TextMarshaler as "C-<number>".
+	Code uint16
+
+	// BrokenMarshaler fails whenever it is marshaled.
+	BrokenMarshaler struct{}
+
+	MarshalerTestData struct {
+		Price     Cents          `json:"price,omitempty"`
+		Code      Code           `json:"code,omitempty"`
+		Addr      netip.Addr     `json:"addr,omitempty"`
+		CreatedAt time.Time      `json:"created_at,omitempty"`
+		Prices    []Cents        `json:"prices,omitempty"`
+		Codes     map[Code]Cents `json:"codes,omitempty"`
+		Discount  *Cents         `json:"discount,omitempty"`
+		Refund    *Cents         `json:"refund,omitempty"`
+	}
+
+	// Coordinates stands for a third-party type; the Updates channel keeps it from being walked reflectively,
+	// so it can only be serialized through a registered codec.
+	Coordinates struct {
+		Lat     float64       `json:"lat,omitempty"`
+		Lng     float64       `json:"lng,omitempty"`
+		Updates chan struct{} `json:"-"`
+	}
+
+	CodecTestData struct {
+		Name   string                 `json:"name,omitempty"`
+		Origin Coordinates            `json:"origin,omitempty"`
+		Stops  []Coordinates          `json:"stops,omitempty"`
+		ByName map[string]Coordinates `json:"by_name,omitempty"`
+		Last   *Coordinates           `json:"last,omitempty"`
+	}
+
+	TimeTestData struct {
+		CreatedAt time.Time                       `json:"created_at,omitempty"`
+		DeletedAt *time.Time                      `json:"deleted_at,omitempty"`
+		Timeout   time.Duration                   `json:"timeout,omitempty"`
+		Events    []time.Time                     `json:"events,omitempty"`
+		Retries   []time.Duration                 `json:"retries,omitempty"`
+		ByTime    map[time.Time]string            `json:"by_time,omitempty"`
+		Deadlines map[string]time.Time            `json:"deadlines,omitempty"`
+		Windows   map[time.Duration]time.Duration `json:"windows,omitempty"`
+	}
+
+	BrokenMarshalerTestData struct {
+		Name   string          `json:"name,omitempty"`
+		Broken BrokenMarshaler `json:"broken,omitempty"`
+	}
+
+	ArrayTestData struct {
+		ID       [16]byte        `json:"id,omitempty"`
+		Hash     [32]uint8       `json:"hash,omitempty"`
+		Counters [4]int64        `json:"counters,omitempty"`
+		Ratios   [3]float64      `json:"ratios,omitempty"`
+		Flags    [3]bool         `json:"flags,omitempty"`
+		Tags     [2]string       `json:"tags,omitempty"`
+		Matrix   [2][3]int32     `json:"matrix,omitempty"`
+		Items    [2]Item         `json:"items,omitempty"`
+		Codes    [2]Code         `json:"codes,omitempty"`
+		Chunks   [2][]byte       `json:"chunks,omitempty"`
+		ByID     map[[4]byte]int `json:"by_id,omitempty"`
+		Empty    [0]int64        `json:"empty,omitempty"`
+	}
+
+	Status string
+	Amount int64
+	Level  uint16
+	Ratio  float64
+	Flag   bool
+	IDs    []int64
+	Tags   []string
+	Blob   []byte
+	Blobs  [][]byte
+
+	NamedTypesTestData struct {
+		Status    Status            `json:"status,omitempty"`
+		Amount    Amount            `json:"amount,omitempty"`
+		Level     Level             `json:"level,omitempty"`
+		Ratio     Ratio             `json:"ratio,omitempty"`
+		Flag      Flag              `json:"flag,omitempty"`
+		IDs       IDs               `json:"ids,omitempty"`
+		Tags      Tags              `json:"tags,omitempty"`
+		Statuses  []Status          `json:"statuses,omitempty"`
+		Amounts   []Amount          `json:"amounts,omitempty"`
+		Levels    []Level           `json:"levels,omitempty"`
+		Ratios    []Ratio           `json:"ratios,omitempty"`
+		Flags     []Flag            `json:"flags,omitempty"`
+		Blob      Blob              `json:"blob,omitempty"`
+		Blobs     Blobs             `json:"blobs,omitempty"`
+		BlobList  []Blob            `json:"blob_list,omitempty"`
+		Codes     []Code            `json:"codes,omitempty"`
+		Durations []time.Duration   `json:"durations,omitempty"`
+		Window    [3]Amount         `json:"window,omitempty"`
+		ByStatus  map[Status]Amount `json:"by_status,omitempty"`
+	}
+
+	EventLine struct {
+		SKU      string `binary:",id=1"`
+		Quantity int32  `binary:",id=2"`
+	}
+
+	EventV1 struct {
+		ID     string      `binary:",id=1"`
+		Kind   uint8       `binary:",id=2"`
+		Amount int64       `binary:",id=3"`
+		Tags   []string    `binary:",id=4"`
+		Lines  []EventLine `binary:",id=5"`
+		Parent *EventLine  `binary:",id=6"`
+	}
+
+	// EventV2 is EventV1 with its fields reordered, Amount removed and Source and Ratio added.
+	EventV2 struct {
+		Lines  []EventLine `binary:",id=5"`
+		ID     string      `binary:",id=1"`
+		Source string      `binary:",id=7"`
+		Tags   []string    `binary:",id=4"`
+		Kind   uint8       `binary:",id=2"`
+		Parent *EventLine  `binary:",id=6"`
+		Ratio  float64     `binary:",id=8"`
+	}
+
+	// EventKindChanged is EventV1 with Kind widened to a different wire type.
+	EventKindChanged struct {
+		ID   string `binary:",id=1"`
+		Kind int64  `binary:",id=2"`
+	}
+
+	MissingFieldIDTestData struct {
+		ID   string `binary:",id=1"`
+		Name string
+	}
+
+	DuplicateFieldIDTestData struct {
+		ID   string `binary:",id=1"`
+		Name string `binary:",id=1"`
+	}
+	// RenamedItem has the layout of Item under another name.
+	RenamedItem struct {
+		Id      string
+		ItemId  uint64
+		Number  int64
+		SubItem *SubItem
+	}
+
+	// ReorderedItem is Item with Number moved before ItemId.
+	ReorderedItem struct {
+		Id      string
+		Number  int64
+		ItemId  uint64
+		SubItem *SubItem
+	}
+
+	TreeNode struct {
+		Value    int64
+		Children []*TreeNode
+		Parent   *TreeNode
+	}
+
+	// InterfaceSliceTestData holds slices of predeclared and empty interfaces, which are not primitive slices.
+	InterfaceSliceTestData struct {
+		Errors []error
+		Values []interface{}
+	}
+
+	// ZeroCopyTestData holds the values that may alias the payload they are decoded from.
+	ZeroCopyTestData struct {
+		String  string
+		Bytes   []byte
+		Chunks  [][]byte
+		Strings []string
+		Labels  map[string]string
+		Any     map[string]interface{}
+		Int64s  []int64
+	}
+)
+
+var (
+	ErrBrokenMarshaler    = errors.New("broken marshaler")
+	ErrInvalidCoordinates = errors.New("invalid coordinates")
 )
+
+func NewCents(value int64) Cents {
+	return Cents{value: value}
+}
+
+func (c Cents) MarshalBinary() ([]byte, error) {
+	return binary.BigEndian.AppendUint64(nil, uint64(c.value)), nil
+}
+
+func (c *Cents) UnmarshalBinary(data []byte) error {
+	if len(data) != 8 {
+		return fmt.Errorf("cents: expected 8 bytes, got %d", len(data))
+	}
+
+	c.value = int64(binary.BigEndian.Uint64(data))
+	return nil
+}
+
+func (c Code) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("C-%d", c)), nil
+}
+
+func (c *Code) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "C-%d", (*uint16)(c))
+	return err
+}
+
+func (BrokenMarshaler) MarshalBinary() ([]byte, error) {
+	return nil, ErrBrokenMarshaler
+}
+
+func (*BrokenMarshaler) UnmarshalBinary([]byte) error {
+	return ErrBrokenMarshaler
+}
+
+func (m Money) String() string {
+	return fmt.Sprintf("%d %s", m.Amount, m.Currency)
+}
+
+func NewUnexportedFieldsTestData(
+	name string, createdAt time.Time, secret string, counter int64, tags []string, sub *SubItem,
+) *UnexportedFieldsTestData {
+	return &UnexportedFieldsTestData{
+		Name:      name,
+		CreatedAt: createdAt,
+		secret:    secret,
+		counter:   counter,
+		tags:      tags,
+		sub:       sub,
+	}
+}
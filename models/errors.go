@@ -0,0 +1,392 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// DecodeError reports a malformed or truncated binary payload.
+type DecodeError struct {
+	// Field is the path of the field being decoded, e.g. "Items[2].Name".
+	// It is empty when the failure happened at the top level value.
+	Field string
+	// Type is the Go type of the innermost value being decoded.
+	Type string
+	// Offset is the byte offset in the payload at which the read was attempted.
+	Offset int
+	// Expected is the number of bytes the read required.
+	Expected int
+	// Remaining is the number of bytes that were left in the payload.
+	Remaining int
+	// Reason describes malformed input other than a truncation, such as an overflowing varint.
+	// Expected and Remaining are not meaningful when it is set.
+	Reason string
+}
+
+func (e *DecodeError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("binary: malformed payload")
+	if e.Field != "" {
+		sb.WriteString(fmt.Sprintf(" at field %q", e.Field))
+	}
+	if e.Type != "" {
+		sb.WriteString(fmt.Sprintf(" (%s)", e.Type))
+	}
+
+	if e.Reason != "" {
+		sb.WriteString(fmt.Sprintf(": %s at offset %d", e.Reason, e.Offset))
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf(
+		": expected %d bytes at offset %d but only %d remaining", e.Expected, e.Offset, e.Remaining,
+	))
+
+	return sb.String()
+}
+
+// PrependField adds name in front of the current field path.
+func (e *DecodeError) PrependField(name string) {
+	e.Field = joinFieldPath(name, e.Field)
+}
+
+func joinFieldPath(parent, child string) string {
+	if parent == "" {
+		return child
+	}
+
+	if child == "" {
+		return parent
+	}
+
+	if strings.HasPrefix(child, "[") {
+		return parent + child
+	}
+
+	return parent + "." + child
+}
+
+// UnsupportedTypeError reports a value whose kind cannot be represented in the binary format,
+// such as channels, functions, unsafe pointers and uintptr.
+type UnsupportedTypeError struct {
+	// Field is the path of the offending field, e.g. "Handlers[0]".
+	// It is empty when the top level value itself is not supported.
+	Field string
+	// Type is the Go type of the offending value.
+	Type reflect.Type
+	// Kind is the unsupported kind found within Type.
+	Kind reflect.Kind
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("binary: unsupported kind %s (%s)", e.Kind, e.Type)
+	}
+
+	return fmt.Sprintf("binary: unsupported kind %s (%s) at field %q", e.Kind, e.Type, e.Field)
+}
+
+// PrependField adds name in front of the current field path.
+func (e *UnsupportedTypeError) PrependField(name string) {
+	e.Field = joinFieldPath(name, e.Field)
+}
+
+// StructTagError reports a malformed `binary` struct tag.
+type StructTagError struct {
+	// Type is the struct type holding the field.
+	Type reflect.Type
+	// Field is the Go name of the field carrying the tag.
+	Field string
+	// Tag is the raw tag value.
+	Tag string
+	// Err describes what is wrong with the tag.
+	Err error
+}
+
+func (e *StructTagError) Error() string {
+	return fmt.Sprintf("binary: invalid struct tag %q on field %s.%s: %v", e.Tag, e.Type, e.Field, e.Err)
+}
+
+func (e *StructTagError) Unwrap() error {
+	return e.Err
+}
+
+// UnregisteredTypeError reports an interface value whose concrete type has not been registered.
+type UnregisteredTypeError struct {
+	// Field is the path of the interface field holding the value.
+	Field string
+	// Type is the unregistered concrete type found while encoding.
+	Type reflect.Type
+	// ID is the unknown type identifier found while decoding.
+	ID uint32
+}
+
+func (e *UnregisteredTypeError) Error() string {
+	var msg string
+	if e.Type != nil {
+		msg = fmt.Sprintf("binary: type %s is not registered", e.Type)
+	} else {
+		msg = fmt.Sprintf("binary: unknown type id %#08x", e.ID)
+	}
+
+	if e.Field != "" {
+		msg += fmt.Sprintf(" at field %q", e.Field)
+	}
+
+	return msg
+}
+
+// PrependField adds name in front of the current field path.
+func (e *UnregisteredTypeError) PrependField(name string) {
+	e.Field = joinFieldPath(name, e.Field)
+}
+
+// InterfaceTypeError reports a registered type decoded into an interface it does not implement.
+type InterfaceTypeError struct {
+	// Field is the path of the interface field.
+	Field string
+	// Type is the registered concrete type found in the payload.
+	Type reflect.Type
+	// Interface is the interface type of the target field.
+	Interface reflect.Type
+}
+
+func (e *InterfaceTypeError) Error() string {
+	msg := fmt.Sprintf("binary: registered type %s is not assignable to %s", e.Type, e.Interface)
+	if e.Field != "" {
+		msg += fmt.Sprintf(" at field %q", e.Field)
+	}
+
+	return msg
+}
+
+// PrependField adds name in front of the current field path.
+func (e *InterfaceTypeError) PrependField(name string) {
+	e.Field = joinFieldPath(name, e.Field)
+}
+
+// MarshalerError reports a failure returned by an encoding.BinaryMarshaler, encoding.TextMarshaler
+// or their unmarshaler counterparts.
+type MarshalerError struct {
+	// Field is the path of the failing field.
+	Field string
+	// Type is the type implementing the failing method.
+	Type reflect.Type
+	// Method is the name of the failing method.
+	Method string
+	// Err is the error returned by the method.
+	Err error
+}
+
+func (e *MarshalerError) Error() string {
+	msg := fmt.Sprintf("binary: error calling %s for type %s", e.Method, e.Type)
+	if e.Field != "" {
+		msg += fmt.Sprintf(" at field %q", e.Field)
+	}
+
+	return msg + ": " + e.Err.Error()
+}
+
+func (e *MarshalerError) Unwrap() error {
+	return e.Err
+}
+
+// PrependField adds name in front of the current field path.
+func (e *MarshalerError) PrependField(name string) {
+	e.Field = joinFieldPath(name, e.Field)
+}
+
+// CodecError reports a failure returned by a codec registered with RegisterCodec.
+type CodecError struct {
+	// Field is the path of the failing field.
+	Field string
+	// Type is the type the codec was registered for.
+	Type reflect.Type
+	// Err is the error returned by the codec.
+	Err error
+}
+
+func (e *CodecError) Error() string {
+	msg := fmt.Sprintf("binary: codec error for type %s", e.Type)
+	if e.Field != "" {
+		msg += fmt.Sprintf(" at field %q", e.Field)
+	}
+
+	return msg + ": " + e.Err.Error()
+}
+
+func (e *CodecError) Unwrap() error {
+	return e.Err
+}
+
+// PrependField adds name in front of the current field path.
+func (e *CodecError) PrependField(name string) {
+	e.Field = joinFieldPath(name, e.Field)
+}
+
+// InvalidTargetError reports a target that payloads cannot be decoded into, because it is not a non-nil pointer.
+type InvalidTargetError struct {
+	// Type is the type of the target, nil when the target itself is nil.
+	Type reflect.Type
+}
+
+func (e *InvalidTargetError) Error() string {
+	if e.Type == nil {
+		return "binary: expected a non-nil pointer, got nil"
+	}
+
+	return fmt.Sprintf("binary: expected a non-nil pointer, got %s", e.Type)
+}
+
+var (
+	// ErrNotEnveloped reports a payload that does not start with the envelope magic bytes.
+	ErrNotEnveloped = errors.New("payload is not enveloped")
+	// ErrUnsupportedVersion reports an envelope written by an unknown revision of the binary format.
+	ErrUnsupportedVersion = errors.New("unsupported format version")
+	// ErrFormatMismatch reports an envelope written by a different binary serializer.
+	ErrFormatMismatch = errors.New("payload written by another serializer")
+	// ErrUnsupportedFlags reports an envelope announcing features the serializer cannot read.
+	ErrUnsupportedFlags = errors.New("unsupported envelope flags")
+)
+
+// EnvelopeError reports an envelope header the serializer cannot read.
+type EnvelopeError struct {
+	// Err is one of ErrNotEnveloped, ErrUnsupportedVersion, ErrFormatMismatch or ErrUnsupportedFlags.
+	Err error
+	// Detail describes the offending header value.
+	Detail string
+}
+
+func (e *EnvelopeError) Error() string {
+	if e.Detail == "" {
+		return "binary: " + e.Err.Error()
+	}
+
+	return "binary: " + e.Err.Error() + ": " + e.Detail
+}
+
+func (e *EnvelopeError) Unwrap() error {
+	return e.Err
+}
+
+// ErrSchemaMismatch reports a payload written for a type whose layout differs from the target's.
+var ErrSchemaMismatch = errors.New("schema mismatch")
+
+// SchemaMismatchError reports a payload whose schema fingerprint differs from the fingerprint of the target type.
+type SchemaMismatchError struct {
+	// Type is the target type.
+	Type reflect.Type
+	// Fingerprint is the fingerprint of Type.
+	Fingerprint uint64
+	// Payload is the fingerprint found in the payload.
+	Payload uint64
+}
+
+func (e *SchemaMismatchError) Error() string {
+	return fmt.Sprintf(
+		"binary: schema mismatch: payload fingerprint %#016x, %s has %#016x", e.Payload, e.Type, e.Fingerprint,
+	)
+}
+
+func (e *SchemaMismatchError) Unwrap() error {
+	return ErrSchemaMismatch
+}
+
+// ErrChecksumMismatch reports a payload whose checksum trailer does not match its content.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// ChecksumError reports a payload corrupted since it was written, detected through its CRC32C trailer.
+type ChecksumError struct {
+	// Expected is the checksum found in the trailer.
+	Expected uint32
+	// Actual is the checksum of the payload as read.
+	Actual uint32
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf(
+		"binary: checksum mismatch: trailer holds %#08x but the payload sums to %#08x", e.Expected, e.Actual,
+	)
+}
+
+func (e *ChecksumError) Unwrap() error {
+	return ErrChecksumMismatch
+}
+
+// ErrUnknownCompression reports a payload whose compression algorithm marker is unknown.
+var ErrUnknownCompression = errors.New("unknown compression algorithm")
+
+// ErrDecompressedTooLarge reports a payload that decompresses past the maximum size allowed.
+var ErrDecompressedTooLarge = errors.New("decompressed payload too large")
+
+// CompressionError reports a payload that could not be compressed or decompressed.
+type CompressionError struct {
+	// Algorithm is the name of the compression algorithm.
+	Algorithm string
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *CompressionError) Error() string {
+	if e.Algorithm == "" {
+		return "compression: " + e.Err.Error()
+	}
+
+	return "compression: " + e.Algorithm + ": " + e.Err.Error()
+}
+
+func (e *CompressionError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	// ErrAuthenticationFailed reports a sealed payload that was tampered with or sealed with another key.
+	ErrAuthenticationFailed = errors.New("message authentication failed")
+	// ErrUnknownKey reports a key id that the key provider does not know about.
+	ErrUnknownKey = errors.New("unknown key")
+)
+
+// EncryptionError reports a payload that could not be sealed or opened.
+type EncryptionError struct {
+	// KeyID is the id of the key the payload is sealed with, when known.
+	KeyID string
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *EncryptionError) Error() string {
+	if e.KeyID == "" {
+		return "encryption: " + e.Err.Error()
+	}
+
+	return fmt.Sprintf("encryption: key %q: %v", e.KeyID, e.Err)
+}
+
+func (e *EncryptionError) Unwrap() error {
+	return e.Err
+}
+
+// ErrSignatureMismatch reports a signed payload that was tampered with or signed with another key.
+var ErrSignatureMismatch = errors.New("signature mismatch")
+
+// SignatureError reports a payload that could not be signed or verified.
+type SignatureError struct {
+	// KeyID is the id of the key the payload is signed with, when known.
+	KeyID string
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *SignatureError) Error() string {
+	if e.KeyID == "" {
+		return "signature: " + e.Err.Error()
+	}
+
+	return fmt.Sprintf("signature: key %q: %v", e.KeyID, e.Err)
+}
+
+func (e *SignatureError) Unwrap() error {
+	return e.Err
+}
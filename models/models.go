@@ -3,17 +3,19 @@ package models
 const (
 	WrongPayloadTypeErrMsg = "wrong payload type"
 	WrongTargetTypeErrMsg  = "wrong target type"
-	EncodeErrMsg           = "failed to encode payload - err: %v"
-	DecodeErrMsg           = "failed to decode payload to into target - err: %v"
+	EncodeErrMsg           = "failed to encode payload - err: %w"
+	DecodeErrMsg           = "failed to decode payload to into target - err: %w"
 
-	RebinderErrMsg = "failed to rebind data - err: %v"
+	RebinderErrMsg = "failed to rebind data - err: %w"
 )
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
 //counterfeiter:generate -o ../../../fakes/fake_serializer.go . Serializer
 //counterfeiter:generate -o ../../../fakes/fake_beautifier.go . Beautifier
+//counterfeiter:generate -o ../../../fakes/fake_codec.go . Codec
 //go:generate mockgen -package fakes -destination ../fakes/fake_serializer.go . Serializer
 //go:generate mockgen -package fakes -destination ../fakes/fake_beautifier.go . Beautifier
+//go:generate mockgen -package fakes -destination ../fakes/fake_codec.go . Codec
 
 type (
 	Serializer interface {
@@ -27,4 +29,10 @@ type (
 		Serializer
 		Beautify(payload interface{}, prefix string, indent string) ([]byte, error)
 	}
+
+	// Codec serializes the values of a single type, sparing the interface{} conversions of Serializer.
+	Codec[T any] interface {
+		Encode(v T) ([]byte, error)
+		Decode(payload []byte) (T, error)
+	}
 )
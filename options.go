@@ -0,0 +1,86 @@
+package serializer
+
+import (
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/binaryx"
+)
+
+// BinaryOption configures BinarySerializer and RawBinarySerializer.
+type BinaryOption = binaryx.Option
+
+// WithSkipUnsupportedFields makes the binary serializers leave out struct fields whose kind cannot be
+// represented in the binary format (channels, functions, unsafe pointers and uintptr)
+// instead of failing with a *models.UnsupportedTypeError.
+func WithSkipUnsupportedFields() BinaryOption {
+	return binaryx.WithSkipUnsupported()
+}
+
+// UnexportedFieldPolicy decides how the binary serializers treat unexported struct fields.
+type UnexportedFieldPolicy = binaryx.UnexportedFieldPolicy
+
+const (
+	// SkipUnexportedFields leaves unexported fields out of the payload, like encoding/json does.
+	// It is the default policy.
+	SkipUnexportedFields = binaryx.SkipUnexported
+	// IncludeUnexportedFields serializes unexported fields as well.
+	// BinarySerializer reaches them through the internal reflectx package, making this policy the only
+	// place where it relies on unsafe operations.
+	IncludeUnexportedFields = binaryx.IncludeUnexported
+)
+
+// WithUnexportedFields sets the policy applied to unexported struct fields.
+//
+// IncludeUnexportedFields makes BinarySerializer use unsafe: reflect refuses to set unexported fields, so they are
+// written through unsafe.Pointer aliases. BinarySerializer only stays free of unsafe with the default policy.
+func WithUnexportedFields(policy UnexportedFieldPolicy) BinaryOption {
+	return binaryx.WithUnexportedFields(policy)
+}
+
+// WithVarint makes the binary serializers write integers as LEB128 varints, zigzag encoding the signed ones,
+// and slice, map, string and byte lengths as unsigned varints. Small numbers then take a single byte instead of
+// up to eight, at the cost of per-element encoding for integer slices.
+//
+// Floats, complex numbers, interface type ids, time.Time values and registered codecs keep their layout, while
+// time.Duration values are zigzag varints like the other signed integers.
+// Payloads written in one mode can only be read in the same mode.
+func WithVarint() BinaryOption {
+	return binaryx.WithVarint()
+}
+
+// WithEnvelope makes Serialize prefix payloads with a header made of magic bytes, the serializer that wrote them,
+// the format version and flags describing the integer mode. Deserialize then expects the header, failing with a
+// *models.EnvelopeError on payloads written by another serializer or an incompatible format version, and reads
+// varint payloads whatever the mode it was configured with. DataRebind never writes the header.
+func WithEnvelope() BinaryOption {
+	return binaryx.WithEnvelope()
+}
+
+// WithFieldIDs makes the binary serializers identify struct fields by the id of their `binary:",id=N"` tag
+// instead of by their position, like protocol buffers field numbers. Each field is preceded by its id and a wire
+// type, so fields can be added, removed and reordered: decoders skip the fields they do not know about and leave
+// the ones missing from the payload zero. Every serialized struct field must then have an id.
+func WithFieldIDs() BinaryOption {
+	return binaryx.WithFieldIDs()
+}
+
+// WithSchemaFingerprint makes Serialize write the fingerprint of the serialized type before the payload, see
+// SchemaFingerprint. Deserialize then fails with a *models.SchemaMismatchError, matching models.ErrSchemaMismatch,
+// when the fingerprint of the target type differs, instead of decoding the payload into the wrong fields.
+// DataRebind never writes the fingerprint.
+func WithSchemaFingerprint() BinaryOption {
+	return binaryx.WithSchemaFingerprint()
+}
+
+// WithChecksum makes Serialize and Marshal append a CRC32C (Castagnoli) trailer computed over the whole payload.
+// Deserialize and Unmarshal verify it before decoding anything, failing with a *models.ChecksumError, matching
+// models.ErrChecksumMismatch, on truncated or corrupted payloads. DataRebind never writes the trailer.
+func WithChecksum() BinaryOption {
+	return binaryx.WithChecksum()
+}
+
+// WithZeroCopy sets whether decoded strings, byte slices and slices of byte slices may alias the payload they were
+// decoded from. Aliasing spares a copy per value, but the decoded values then change along with the payload, so it
+// must not be reused, e.g. as a network read buffer, while they are in use. With WithZeroCopy(false), decoded values
+// never share memory with the payload. BinarySerializer copies by default, RawBinarySerializer aliases by default.
+func WithZeroCopy(enabled bool) BinaryOption {
+	return binaryx.WithZeroCopy(enabled)
+}
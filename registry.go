@@ -0,0 +1,18 @@
+package serializer
+
+import (
+	"reflect"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/binaryx"
+)
+
+// Register records the concrete type of value under name, like gob.Register, so that the binary serializers
+// can encode and decode it when held by interface{} fields, slice elements and map values.
+//
+// The name identifies the type on the wire and must be the same in every process exchanging payloads.
+// Booleans, strings, numbers, []byte, []interface{}, map[string]interface{}, time.Time and time.Duration
+// are registered by default.
+// Register panics when name or the type of value are already registered differently.
+func Register(name string, value interface{}) {
+	binaryx.Register(name, reflect.TypeOf(value))
+}
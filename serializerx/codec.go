@@ -0,0 +1,17 @@
+package serializerx
+
+import (
+	"gitlab.com/pietroski-software-company/devex/golang/serializer"
+)
+
+// Writer is handed to the encoding functions registered with RegisterCodec.
+type Writer = serializer.Writer
+
+// Reader is handed to the decoding functions registered with RegisterCodec.
+type Reader = serializer.Reader
+
+// RegisterCodec makes the binary serializers encode and decode every value of type T through enc and dec.
+// It is the same registry as serializer.RegisterCodec; see its documentation for the rules.
+func RegisterCodec[T any](enc func(*Writer, T) error, dec func(*Reader, *T) error) {
+	serializer.RegisterCodec(enc, dec)
+}
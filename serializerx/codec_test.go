@@ -0,0 +1,51 @@
+//go:build unit
+
+package serializerx
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/testmodels"
+)
+
+func init() {
+	RegisterCodec(encodeCoordinates, decodeCoordinates)
+}
+
+func encodeCoordinates(w *Writer, c testmodels.Coordinates) error {
+	if math.Abs(c.Lat) > 90 || math.Abs(c.Lng) > 180 {
+		return testmodels.ErrInvalidCoordinates
+	}
+
+	w.WriteUint64(math.Float64bits(c.Lat))
+	w.WriteUint64(math.Float64bits(c.Lng))
+	return nil
+}
+
+func decodeCoordinates(r *Reader, c *testmodels.Coordinates) error {
+	lat, err := r.ReadUint64()
+	if err != nil {
+		return err
+	}
+
+	lng, err := r.ReadUint64()
+	if err != nil {
+		return err
+	}
+
+	c.Lat, c.Lng = math.Float64frombits(lat), math.Float64frombits(lng)
+	if math.Abs(c.Lat) > 90 || math.Abs(c.Lng) > 180 {
+		return testmodels.ErrInvalidCoordinates
+	}
+
+	return nil
+}
+
+func TestRegisterCodec(t *testing.T) {
+	t.Run("duplicate codec", func(t *testing.T) {
+		assert.Panics(t, func() { RegisterCodec(encodeCoordinates, decodeCoordinates) })
+	})
+}
@@ -0,0 +1,14 @@
+package serializerx
+
+import (
+	"reflect"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/binaryx"
+)
+
+// SchemaFingerprint returns the fingerprint that the serializer configured with opts embeds for values of the type
+// of v when WithSchemaFingerprint is set. It hashes the names, ids, kinds and order of the serialized struct fields,
+// down to the nested types, and is stable across processes as long as that layout is unchanged.
+func SchemaFingerprint(v interface{}, opts ...BinaryOption) uint64 {
+	return binaryx.Fingerprint(reflect.TypeOf(v), binaryx.NewOptions(opts...))
+}
@@ -0,0 +1,37 @@
+package serializerx
+
+import (
+	"reflect"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/binaryx"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
+)
+
+func init() {
+	binaryx.RegisterTyped(func(s interface{}, typ reflect.Type) (binaryx.TypedCodec, bool) {
+		if s, ok := s.(*BinarySerializer); ok {
+			if tc, ok := s.typedCodec(typ); ok {
+				return *tc, true
+			}
+		}
+
+		return binaryx.TypedCodec{}, false
+	})
+}
+
+// Encode returns the payload of v serialized by s. It is serializer.Encode; see its documentation.
+func Encode[T any](s models.Serializer, v T) ([]byte, error) {
+	return serializer.Encode(s, v)
+}
+
+// Decode returns the value of T deserialized from payload by s. It is serializer.Decode; see its documentation.
+func Decode[T any](s models.Serializer, payload []byte) (T, error) {
+	return serializer.Decode[T](s, payload)
+}
+
+// NewCodec returns the models.Codec serializing the values of T with s. It is serializer.NewCodec; see its
+// documentation.
+func NewCodec[T any](s models.Serializer) models.Codec[T] {
+	return serializer.NewCodec[T](s)
+}
@@ -0,0 +1,111 @@
+package serializer
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
+)
+
+// SigningSerializer decorates a models.Serializer, appending an HMAC-SHA256 tag to the payloads it serializes and
+// verifying it before they are deserialized, so that tampered payloads never reach the inner serializer. The payload
+// is followed by the id of the signing key, its length on a single byte, and the tag authenticating all of them.
+// Payloads are signed, not encrypted: they remain readable by anyone.
+type SigningSerializer struct {
+	inner models.Serializer
+	keys  KeyProvider
+}
+
+// WithSigning wraps inner into a SigningSerializer signing payloads with the keys of keys. Any key length is accepted,
+// although keys should be at least 32 random bytes long.
+func WithSigning(inner models.Serializer, keys KeyProvider) *SigningSerializer {
+	return &SigningSerializer{
+		inner: inner,
+		keys:  keys,
+	}
+}
+
+func (s *SigningSerializer) Serialize(payload interface{}) ([]byte, error) {
+	bs, err := s.inner.Serialize(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	signed, err := s.sign(bs)
+	if err != nil {
+		return nil, fmt.Errorf(models.EncodeErrMsg, err)
+	}
+
+	return signed, nil
+}
+
+func (s *SigningSerializer) Deserialize(payload []byte, target interface{}) error {
+	bs, err := s.verify(payload)
+	if err != nil {
+		return fmt.Errorf(models.DecodeErrMsg, err)
+	}
+
+	return s.inner.Deserialize(bs, target)
+}
+
+// DataRebind hands over to the inner serializer, as the payload never leaves the process.
+func (s *SigningSerializer) DataRebind(payload interface{}, target interface{}) error {
+	return s.inner.DataRebind(payload, target)
+}
+
+// ################################################################################################################## \\
+// signing
+// ################################################################################################################## \\
+
+var errMalformedSigned = errors.New("malformed signed payload")
+
+func (s *SigningSerializer) sign(bs []byte) ([]byte, error) {
+	keyID, key, err := s.keys.CurrentKey()
+	if err != nil {
+		return nil, &models.SignatureError{KeyID: keyID, Err: err}
+	}
+
+	if len(keyID) > maxKeyIDLen {
+		return nil, &models.SignatureError{
+			KeyID: keyID,
+			Err:   fmt.Errorf("key id longer than %d bytes", maxKeyIDLen),
+		}
+	}
+
+	signed := make([]byte, 0, len(bs)+len(keyID)+1+sha256.Size)
+	signed = append(signed, bs...)
+	signed = append(signed, keyID...)
+	signed = append(signed, byte(len(keyID)))
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(signed)
+	return mac.Sum(signed), nil
+}
+
+func (s *SigningSerializer) verify(payload []byte) ([]byte, error) {
+	if len(payload) < 1+sha256.Size {
+		return nil, &models.SignatureError{Err: errMalformedSigned}
+	}
+
+	signedLen := len(payload) - sha256.Size
+	keyIDLen := int(payload[signedLen-1])
+	if signedLen < 1+keyIDLen {
+		return nil, &models.SignatureError{Err: errMalformedSigned}
+	}
+
+	keyID := string(payload[signedLen-1-keyIDLen : signedLen-1])
+	key, err := s.keys.Key(keyID)
+	if err != nil {
+		return nil, &models.SignatureError{KeyID: keyID, Err: err}
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload[:signedLen])
+	if !hmac.Equal(mac.Sum(nil), payload[signedLen:]) {
+		return nil, &models.SignatureError{KeyID: keyID, Err: models.ErrSignatureMismatch}
+	}
+
+	return payload[:signedLen-1-keyIDLen], nil
+}
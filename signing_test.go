@@ -0,0 +1,124 @@
+//go:build unit
+
+package serializer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/fakes"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/testmodels"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
+)
+
+func TestSigningSerializer(t *testing.T) {
+	keys := KeyRing{
+		Current: "v2",
+		Keys: map[string][]byte{
+			"v1": bytes.Repeat([]byte{1}, 32),
+			"v2": bytes.Repeat([]byte{2}, 32),
+		},
+	}
+	item := testmodels.Item{
+		Id:      "cursor",
+		ItemId:  7,
+		Number:  -42,
+		SubItem: &testmodels.SubItem{Date: 1_700_000_000, Amount: 10},
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		for name, inner := range map[string]models.Serializer{
+			"binary": NewBinarySerializer(),
+			"json":   NewJsonSerializer(),
+		} {
+			t.Run(name, func(t *testing.T) {
+				s := WithSigning(inner, keys)
+
+				raw, err := inner.Serialize(item)
+				require.NoError(t, err)
+
+				bs, err := s.Serialize(item)
+				require.NoError(t, err)
+				assert.Equal(t, raw, bs[:len(raw)])
+				assert.Len(t, bs, len(raw)+len("v2")+1+32)
+
+				var target testmodels.Item
+				require.NoError(t, s.Deserialize(bs, &target))
+				assert.Equal(t, item, target)
+			})
+		}
+	})
+
+	t.Run("key rotation", func(t *testing.T) {
+		old := keys
+		old.Current = "v1"
+
+		bs, err := WithSigning(NewBinarySerializer(), old).Serialize(item)
+		require.NoError(t, err)
+
+		var target testmodels.Item
+		require.NoError(t, WithSigning(NewBinarySerializer(), keys).Deserialize(bs, &target))
+		assert.Equal(t, item, target)
+
+		retired := KeyRing{Current: "v2", Keys: map[string][]byte{"v2": keys.Keys["v2"]}}
+		err = WithSigning(NewBinarySerializer(), retired).Deserialize(bs, &target)
+		assert.ErrorIs(t, err, models.ErrUnknownKey)
+
+		var signatureErr *models.SignatureError
+		require.ErrorAs(t, err, &signatureErr)
+		assert.Equal(t, "v1", signatureErr.KeyID)
+	})
+
+	t.Run("tampered payloads never reach the inner serializer", func(t *testing.T) {
+		bs, err := WithSigning(NewBinarySerializer(), keys).Serialize(item)
+		require.NoError(t, err)
+
+		ctrl := gomock.NewController(t)
+		inner := fakes.NewMockSerializer(ctrl)
+		inner.EXPECT().Deserialize(gomock.Any(), gomock.Any()).Times(0)
+
+		s := WithSigning(inner, keys)
+		for i := range bs {
+			tampered := bytes.Clone(bs)
+			tampered[i] ^= 1
+
+			var target testmodels.Item
+			err = s.Deserialize(tampered, &target)
+
+			var signatureErr *models.SignatureError
+			assert.ErrorAs(t, err, &signatureErr, "byte %d", i)
+		}
+
+		for cut := 0; cut < len(bs); cut++ {
+			var target testmodels.Item
+			assert.Error(t, s.Deserialize(bs[:cut], &target), "cut at %d", cut)
+		}
+
+		// same id, different key
+		forged := KeyRing{Current: "v2", Keys: map[string][]byte{"v2": bytes.Repeat([]byte{3}, 32)}}
+		forgedBs, err := WithSigning(NewBinarySerializer(), forged).Serialize(item)
+		require.NoError(t, err)
+
+		var target testmodels.Item
+		assert.ErrorIs(t, s.Deserialize(forgedBs, &target), models.ErrSignatureMismatch)
+	})
+
+	t.Run("unknown current key", func(t *testing.T) {
+		_, err := WithSigning(NewBinarySerializer(), KeyRing{Current: "missing"}).Serialize(item)
+		assert.ErrorIs(t, err, models.ErrUnknownKey)
+	})
+
+	t.Run("data rebind", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		inner := fakes.NewMockSerializer(ctrl)
+
+		payload, target := testmodels.Item{Id: "id"}, &testmodels.Item{}
+		inner.EXPECT().DataRebind(payload, target).Return(nil)
+
+		assert.NoError(t, WithSigning(inner, keys).DataRebind(payload, target))
+	})
+}
@@ -0,0 +1,130 @@
+package serializer
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/bytesx"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
+)
+
+// Streams are sequences of frames, each one holding a BinarySerializer payload prefixed with its length as an
+// unsigned varint. Frames carry whatever the options put in payloads, such as envelopes or checksums.
+
+// largeFrameSize is the frame length from which Decoder grows frames as their bytes arrive, rather than trusting
+// lengths read from the stream with allocations of their size.
+const largeFrameSize = 1 << 16
+
+// Encoder writes BinarySerializer payloads to a stream.
+type Encoder struct {
+	s   *BinarySerializer
+	w   *bufio.Writer
+	buf []byte
+}
+
+// NewEncoder returns an Encoder writing to w the payloads of a BinarySerializer configured with opts.
+func NewEncoder(w io.Writer, opts ...BinaryOption) *Encoder {
+	return &Encoder{
+		s:   NewBinarySerializer(opts...),
+		w:   bufio.NewWriter(w),
+		buf: make([]byte, 0, 1<<6),
+	}
+}
+
+// Encode writes the frame of v to the stream. Frames are buffered, so callers must call Flush once they are done
+// encoding for the last frames to reach the underlying writer; values that fail to encode leave the stream untouched.
+func (e *Encoder) Encode(v interface{}) error {
+	bs, err := e.s.serializeTo(bytesx.NewAppendWriter(e.buf), v)
+	if err != nil {
+		return fmt.Errorf(models.EncodeErrMsg, err)
+	}
+
+	// the buffer grown by the largest payload so far is reused by the following ones
+	e.buf = bs[:0]
+
+	var length [binary.MaxVarintLen64]byte
+	_, _ = e.w.Write(length[:binary.PutUvarint(length[:], uint64(len(bs)))])
+	_, err = e.w.Write(bs)
+	return err
+}
+
+// Flush writes the buffered frames to the underlying writer.
+func (e *Encoder) Flush() error {
+	return e.w.Flush()
+}
+
+// Decoder reads BinarySerializer payloads from a stream.
+type Decoder struct {
+	s *BinarySerializer
+	r *bufio.Reader
+}
+
+// NewDecoder returns a Decoder reading from r the payloads of a BinarySerializer configured with opts.
+// The Decoder buffers its reads, so it may read from r past the frames decoded so far.
+func NewDecoder(r io.Reader, opts ...BinaryOption) *Decoder {
+	return &Decoder{
+		s: NewBinarySerializer(opts...),
+		r: bufio.NewReader(r),
+	}
+}
+
+// Decode reads the next frame into target. It returns io.EOF once the stream ends after a whole frame and
+// io.ErrUnexpectedEOF when it ends in the middle of one. Frames whose payload fails to decode are consumed all the
+// same, so that decoding can carry on with the following frames.
+func (d *Decoder) Decode(target interface{}) error {
+	length, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return err
+		}
+
+		return fmt.Errorf(models.DecodeErrMsg, err)
+	}
+
+	frame, err := d.readFrame(length)
+	if err != nil {
+		return err
+	}
+
+	if err = d.s.deserialize(frame, target); err != nil {
+		return fmt.Errorf(models.DecodeErrMsg, err)
+	}
+
+	return nil
+}
+
+// readFrame reads the length bytes of a frame. A new buffer is allocated for every frame, as decoded values may
+// alias it.
+func (d *Decoder) readFrame(length uint64) ([]byte, error) {
+	if length < largeFrameSize {
+		frame := make([]byte, length)
+		if _, err := io.ReadFull(d.r, frame); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, io.ErrUnexpectedEOF
+			}
+
+			return nil, err
+		}
+
+		return frame, nil
+	}
+
+	if length > math.MaxInt64 {
+		return nil, fmt.Errorf(models.DecodeErrMsg, fmt.Errorf("frame length %d overflows", length))
+	}
+
+	frame, err := io.ReadAll(io.LimitReader(d.r, int64(length)))
+	if err != nil {
+		return nil, err
+	}
+
+	if uint64(len(frame)) != length {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	return frame, nil
+}
@@ -0,0 +1,216 @@
+//go:build unit
+
+package serializer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/testmodels"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+type countingWriter struct {
+	buf    bytes.Buffer
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.buf.Write(p)
+}
+
+func TestStream(t *testing.T) {
+	items := compressionTestItems(100)
+
+	encodeAll := func(t *testing.T, opts ...BinaryOption) []byte {
+		var buf bytes.Buffer
+		enc := NewEncoder(&buf, opts...)
+		for _, item := range items {
+			require.NoError(t, enc.Encode(item))
+		}
+		require.NoError(t, enc.Flush())
+
+		return buf.Bytes()
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		for name, opts := range map[string][]BinaryOption{
+			"default":  nil,
+			"varint":   {WithVarint()},
+			"envelope": {WithEnvelope(), WithChecksum(), WithSchemaFingerprint()},
+		} {
+			t.Run(name, func(t *testing.T) {
+				dec := NewDecoder(bytes.NewReader(encodeAll(t, opts...)), opts...)
+				for _, item := range items {
+					var target testmodels.Item
+					require.NoError(t, dec.Decode(&target))
+					assert.Equal(t, item, target)
+				}
+
+				var target testmodels.Item
+				assert.Equal(t, io.EOF, dec.Decode(&target))
+				assert.Equal(t, io.EOF, dec.Decode(&target))
+			})
+		}
+	})
+
+	t.Run("frames", func(t *testing.T) {
+		s := NewBinarySerializer()
+		bbr := bytes.NewReader(encodeAll(t))
+		for _, item := range items {
+			length, err := binary.ReadUvarint(bbr)
+			require.NoError(t, err)
+
+			frame := make([]byte, length)
+			_, err = io.ReadFull(bbr, frame)
+			require.NoError(t, err)
+
+			bs, err := s.Serialize(item)
+			require.NoError(t, err)
+			assert.Equal(t, bs, frame)
+		}
+	})
+
+	t.Run("empty stream", func(t *testing.T) {
+		var target testmodels.Item
+		assert.Equal(t, io.EOF, NewDecoder(bytes.NewReader(nil)).Decode(&target))
+	})
+
+	t.Run("truncated stream", func(t *testing.T) {
+		bs := encodeAll(t)[:200]
+
+		dec := NewDecoder(bytes.NewReader(bs))
+		var err error
+		for err == nil {
+			var target testmodels.Item
+			err = dec.Decode(&target)
+		}
+
+		assert.Equal(t, io.ErrUnexpectedEOF, err)
+	})
+
+	t.Run("large frames", func(t *testing.T) {
+		large := testmodels.Item{Id: strings.Repeat("large", 100_000)}
+
+		var buf bytes.Buffer
+		enc := NewEncoder(&buf)
+		require.NoError(t, enc.Encode(large))
+		require.NoError(t, enc.Encode(items[0]))
+		require.NoError(t, enc.Flush())
+
+		dec := NewDecoder(&buf)
+		var target testmodels.Item
+		require.NoError(t, dec.Decode(&target))
+		assert.Equal(t, large, target)
+
+		require.NoError(t, dec.Decode(&target))
+		assert.Equal(t, items[0], target)
+	})
+
+	t.Run("lying frame length", func(t *testing.T) {
+		bs := binary.AppendUvarint(nil, 1<<40)
+		bs = append(bs, 1, 2, 3)
+
+		var target testmodels.Item
+		assert.Equal(t, io.ErrUnexpectedEOF, NewDecoder(bytes.NewReader(bs)).Decode(&target))
+	})
+
+	t.Run("decoding carries on after a bad frame", func(t *testing.T) {
+		var buf bytes.Buffer
+		enc := NewEncoder(&buf)
+		require.NoError(t, enc.Encode(items[0]))
+		require.NoError(t, enc.Encode("not an item"))
+		require.NoError(t, enc.Encode(items[1]))
+		require.NoError(t, enc.Flush())
+
+		dec := NewDecoder(&buf)
+		var target testmodels.Item
+		require.NoError(t, dec.Decode(&target))
+
+		var decodeErr *models.DecodeError
+		assert.ErrorAs(t, dec.Decode(&target), &decodeErr)
+
+		target = testmodels.Item{}
+		require.NoError(t, dec.Decode(&target))
+		assert.Equal(t, items[1], target)
+	})
+
+	t.Run("encoding errors", func(t *testing.T) {
+		var buf bytes.Buffer
+		enc := NewEncoder(&buf)
+		assert.Error(t, enc.Encode(make(chan int)))
+		require.NoError(t, enc.Flush())
+		assert.Zero(t, buf.Len())
+
+		enc = NewEncoder(failingWriter{})
+		require.NoError(t, enc.Encode(items[0]))
+		assert.Error(t, enc.Flush())
+	})
+
+	t.Run("frames are batched until flushed", func(t *testing.T) {
+		var w countingWriter
+		enc := NewEncoder(&w)
+		for _, item := range items[:10] {
+			require.NoError(t, enc.Encode(item))
+		}
+		assert.Zero(t, w.writes)
+
+		require.NoError(t, enc.Flush())
+		assert.Equal(t, 1, w.writes)
+
+		dec := NewDecoder(&w.buf)
+		for _, item := range items[:10] {
+			var target testmodels.Item
+			require.NoError(t, dec.Decode(&target))
+			assert.Equal(t, item, target)
+		}
+	})
+
+	t.Run("pipe", func(t *testing.T) {
+		r, w := io.Pipe()
+		go func() {
+			enc := NewEncoder(w)
+			for _, item := range items {
+				if err := enc.Encode(item); err != nil {
+					_ = w.CloseWithError(err)
+					return
+				}
+			}
+			if err := enc.Flush(); err != nil {
+				_ = w.CloseWithError(err)
+				return
+			}
+
+			_ = w.Close()
+		}()
+
+		dec := NewDecoder(r)
+		var decoded []testmodels.Item
+		for {
+			var target testmodels.Item
+			err := dec.Decode(&target)
+			if err == io.EOF {
+				break
+			}
+
+			require.NoError(t, err)
+			decoded = append(decoded, target)
+		}
+
+		assert.Equal(t, items, decoded)
+	})
+}
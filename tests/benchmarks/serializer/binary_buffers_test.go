@@ -0,0 +1,99 @@
+//go:build benchmark
+
+package serializer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/testmodels"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/serializerx"
+)
+
+// bufferedSerializer is implemented by the binary serializers, whose buffers come from a pool.
+type bufferedSerializer interface {
+	Serialize(data interface{}) ([]byte, error)
+	SerializeTo(data interface{}, fn func(bs []byte) error) error
+	Append(dst []byte, data interface{}) ([]byte, error)
+}
+
+// BenchmarkBinarySerializerBuffers compares the ways the binary serializers hand over payloads: Serialize copies them
+// out of a pooled buffer, SerializeTo lends the pooled buffer and Append writes into a buffer owned by the caller.
+func BenchmarkBinarySerializerBuffers(b *testing.B) {
+	serializers := []struct {
+		name string
+		s    bufferedSerializer
+	}{
+		{name: "binary", s: serializer.NewBinarySerializer()},
+		{name: "raw binary", s: serializer.NewRawBinarySerializer()},
+		{name: "x binary", s: serializerx.NewBinarySerializer()},
+	}
+
+	stringList := make([]string, 1_000)
+	for i := range stringList {
+		stringList[i] = strings.Repeat("item", i%16)
+	}
+
+	cases := []struct {
+		name string
+		msg  interface{}
+	}{
+		{
+			name: "item sample",
+			msg: &testmodels.Item{
+				Id:     "any-item",
+				ItemId: 100,
+				Number: 5_000_000_000,
+				SubItem: &testmodels.SubItem{
+					Date:     time.Now().Unix(),
+					Amount:   1_000_000_000,
+					ItemCode: "code-status",
+				},
+			},
+		},
+		{
+			name: "large []string",
+			msg:  &testmodels.StringSliceTestData{StringList: stringList},
+		},
+	}
+
+	for _, sc := range serializers {
+		b.Run(sc.name, func(b *testing.B) {
+			for _, tc := range cases {
+				b.Run(tc.name, func(b *testing.B) {
+					bs, err := sc.s.Serialize(tc.msg)
+					require.NoError(b, err)
+
+					b.Run("serialize", func(b *testing.B) {
+						b.ReportAllocs()
+						for i := 0; i < b.N; i++ {
+							_, _ = sc.s.Serialize(tc.msg)
+						}
+						b.ReportMetric(float64(len(bs)), "payload-bytes")
+					})
+
+					b.Run("serialize to", func(b *testing.B) {
+						b.ReportAllocs()
+						for i := 0; i < b.N; i++ {
+							_ = sc.s.SerializeTo(tc.msg, func([]byte) error { return nil })
+						}
+						b.ReportMetric(float64(len(bs)), "payload-bytes")
+					})
+
+					b.Run("append", func(b *testing.B) {
+						b.ReportAllocs()
+						dst := make([]byte, 0, len(bs))
+						for i := 0; i < b.N; i++ {
+							dst, _ = sc.s.Append(dst[:0], tc.msg)
+						}
+						b.ReportMetric(float64(len(bs)), "payload-bytes")
+					})
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+//go:build benchmark
+
+package serializer
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer"
+	grpc_item "gitlab.com/pietroski-software-company/devex/golang/serializer/internal/generated/go/pkg/item"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/testmodels"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/serializerx"
+)
+
+// BenchmarkBinarySerializerPlans compares the binary serializers with proto on collections of structs, where the
+// type plans compiled by the binary serializers spare most of the reflection done per element.
+func BenchmarkBinarySerializerPlans(b *testing.B) {
+	const size = 100
+
+	structList := make([]*testmodels.StructTestData, size)
+	protoStructList := make([]*grpc_item.StructTestData, size)
+	structMap := make(map[string]testmodels.StructTestData, size)
+	protoStructMap := make(map[string]*grpc_item.StructTestData, size)
+	for i := 0; i < size; i++ {
+		key := "key-" + strconv.Itoa(i)
+		structList[i] = &testmodels.StructTestData{Bool: i%2 == 0, String: key, Int64: int64(i) << 32}
+		protoStructList[i] = &grpc_item.StructTestData{Bool: i%2 == 0, Str: key, Int64: int64(i) << 32}
+		structMap[key] = *structList[i]
+		protoStructMap[key] = protoStructList[i]
+	}
+
+	cases := []struct {
+		name   string
+		msg    interface{}
+		target func() interface{}
+		proto  interface{}
+		pTgt   func() interface{}
+	}{
+		{
+			name:   "[]*StructTestData",
+			msg:    &testmodels.StructPointerSliceTestData{StructPointerList: structList},
+			target: func() interface{} { return &testmodels.StructPointerSliceTestData{} },
+			proto:  &grpc_item.StructSliceTestData{StructList: protoStructList},
+			pTgt:   func() interface{} { return &grpc_item.StructSliceTestData{} },
+		},
+		{
+			name:   "map[string]StructTestData",
+			msg:    &testmodels.MapStringStructTestData{MapStringStruct: structMap},
+			target: func() interface{} { return &testmodels.MapStringStructTestData{} },
+			proto:  &grpc_item.MapStringStructPointerTestData{MapStringStructPointerTestData: protoStructMap},
+			pTgt:   func() interface{} { return &grpc_item.MapStringStructPointerTestData{} },
+		},
+	}
+
+	serializers := []struct {
+		name  string
+		s     models.Serializer
+		proto bool
+	}{
+		{name: "binary", s: serializer.NewBinarySerializer()},
+		{name: "raw binary", s: serializer.NewRawBinarySerializer()},
+		{name: "x binary", s: serializerx.NewBinarySerializer()},
+		{name: "proto", s: serializer.NewProtoSerializer(), proto: true},
+	}
+
+	for _, tc := range cases {
+		b.Run(tc.name, func(b *testing.B) {
+			for _, sc := range serializers {
+				b.Run(sc.name, func(b *testing.B) {
+					msg, target := tc.msg, tc.target()
+					if sc.proto {
+						msg, target = tc.proto, tc.pTgt()
+					}
+
+					bs, err := sc.s.Serialize(msg)
+					require.NoError(b, err)
+					require.NoError(b, sc.s.Deserialize(bs, target))
+
+					b.Run("encoding", func(b *testing.B) {
+						b.ReportAllocs()
+						for i := 0; i < b.N; i++ {
+							_, _ = sc.s.Serialize(msg)
+						}
+					})
+
+					b.Run("decoding", func(b *testing.B) {
+						b.ReportAllocs()
+						for i := 0; i < b.N; i++ {
+							_ = sc.s.Deserialize(bs, target)
+						}
+					})
+				})
+			}
+		})
+	}
+}
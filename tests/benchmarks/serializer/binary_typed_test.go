@@ -0,0 +1,90 @@
+//go:build benchmark
+
+package serializer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/testmodels"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/serializerx"
+)
+
+// BenchmarkBinarySerializerTyped compares Serialize and Deserialize with the generic Encode, Decode and NewCodec,
+// which resolve the plan of their type parameter once.
+func BenchmarkBinarySerializerTyped(b *testing.B) {
+	msg := &testmodels.SimplifiedSpecialStructTestData{
+		Bool:    true,
+		String:  "typed",
+		Int32:   -32,
+		Int64:   64,
+		Uint32:  32,
+		Uint64:  64,
+		Float32: 3.2,
+		Float64: 6.4,
+	}
+
+	serializers := []struct {
+		name string
+		s    models.Serializer
+	}{
+		{name: "binary", s: serializer.NewBinarySerializer()},
+		{name: "raw binary", s: serializer.NewRawBinarySerializer()},
+		{name: "x binary", s: serializerx.NewBinarySerializer()},
+	}
+
+	for _, sc := range serializers {
+		b.Run(sc.name, func(b *testing.B) {
+			bs, err := sc.s.Serialize(msg)
+			require.NoError(b, err)
+
+			c := serializer.NewCodec[*testmodels.SimplifiedSpecialStructTestData](sc.s)
+
+			b.Run("Serialize", func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					_, _ = sc.s.Serialize(msg)
+				}
+			})
+
+			b.Run("Encode", func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					_, _ = serializer.Encode(sc.s, msg)
+				}
+			})
+
+			b.Run("Codec.Encode", func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					_, _ = c.Encode(msg)
+				}
+			})
+
+			b.Run("Deserialize", func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					var target testmodels.SimplifiedSpecialStructTestData
+					_ = sc.s.Deserialize(bs, &target)
+				}
+			})
+
+			b.Run("Decode", func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					_, _ = serializer.Decode[testmodels.SimplifiedSpecialStructTestData](sc.s, bs)
+				}
+			})
+
+			b.Run("Codec.Decode", func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					_, _ = c.Decode(bs)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,122 @@
+//go:build benchmark
+
+package serializer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/testmodels"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/serializerx"
+)
+
+// BenchmarkBinarySerializerVarint compares the fixed-width and varint layouts of the binary serializers,
+// reporting the payload size of every case next to its speed.
+func BenchmarkBinarySerializerVarint(b *testing.B) {
+	serializers := []struct {
+		name   string
+		fixed  models.Serializer
+		varint models.Serializer
+	}{
+		{
+			name:   "binary",
+			fixed:  serializer.NewBinarySerializer(),
+			varint: serializer.NewBinarySerializer(serializer.WithVarint()),
+		},
+		{
+			name:   "raw binary",
+			fixed:  serializer.NewRawBinarySerializer(),
+			varint: serializer.NewRawBinarySerializer(serializer.WithVarint()),
+		},
+		{
+			name:   "x binary",
+			fixed:  serializerx.NewBinarySerializer(),
+			varint: serializerx.NewBinarySerializer(serializerx.WithVarint()),
+		},
+	}
+
+	cases := []struct {
+		name   string
+		msg    interface{}
+		target func() interface{}
+	}{
+		{
+			name: "item sample",
+			msg: &testmodels.Item{
+				Id:     "any-item",
+				ItemId: 100,
+				Number: 5_000_000_000,
+				SubItem: &testmodels.SubItem{
+					Date:     time.Now().Unix(),
+					Amount:   1_000_000_000,
+					ItemCode: "code-status",
+				},
+			},
+			target: func() interface{} { return &testmodels.Item{} },
+		},
+		{
+			name: "[]int64",
+			msg: &testmodels.Int64SliceTestData{
+				Int64List: []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			},
+			target: func() interface{} { return &testmodels.Int64SliceTestData{} },
+		},
+		{
+			name: "[]string",
+			msg: &testmodels.StringSliceTestData{
+				StringList: []string{"first-item", "second-item", "third-item", "fourth-item"},
+			},
+			target: func() interface{} { return &testmodels.StringSliceTestData{} },
+		},
+		{
+			name: "map[int64]int64",
+			msg: &testmodels.MapTestData{
+				Int64KeyMapInt64Value: map[int64]int64{1: 10, 2: 20, 3: 30, 4: 40},
+			},
+			target: func() interface{} { return &testmodels.MapTestData{} },
+		},
+	}
+
+	for _, sc := range serializers {
+		b.Run(sc.name, func(b *testing.B) {
+			for _, tc := range cases {
+				b.Run(tc.name, func(b *testing.B) {
+					for _, mode := range []struct {
+						name string
+						s    models.Serializer
+					}{
+						{name: "fixed", s: sc.fixed},
+						{name: "varint", s: sc.varint},
+					} {
+						b.Run(mode.name, func(b *testing.B) {
+							bs, err := mode.s.Serialize(tc.msg)
+							require.NoError(b, err)
+							target := tc.target()
+							err = mode.s.Deserialize(bs, target)
+							require.NoError(b, err)
+							require.EqualExportedValues(b, tc.msg, target)
+
+							b.Run("encoding", func(b *testing.B) {
+								for i := 0; i < b.N; i++ {
+									_, _ = mode.s.Serialize(tc.msg)
+								}
+								b.ReportMetric(float64(len(bs)), "payload-bytes")
+							})
+
+							b.Run("decoding", func(b *testing.B) {
+								for i := 0; i < b.N; i++ {
+									_ = mode.s.Deserialize(bs, target)
+								}
+								b.ReportMetric(float64(len(bs)), "payload-bytes")
+							})
+						})
+					}
+				})
+			}
+		})
+	}
+}
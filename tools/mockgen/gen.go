@@ -6,3 +6,4 @@ import (
 
 //go:generate mockgen -package mocks -destination ../../mocks/mocked_serializer.go gitlab.com/pietroski-software-company/devex/golang/serializer/models Serializer
 //go:generate mockgen -package mocks -destination ../../mocks/mocked_beautifier.go gitlab.com/pietroski-software-company/devex/golang/serializer/models Beautifier
+//go:generate mockgen -package mocks -destination ../../mocks/mocked_codec.go gitlab.com/pietroski-software-company/devex/golang/serializer/models Codec
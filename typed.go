@@ -0,0 +1,114 @@
+package serializer
+
+import (
+	"reflect"
+	"unsafe"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/binaryx"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
+)
+
+func init() {
+	binaryx.RegisterTyped(typedCodecOf)
+}
+
+// typedCodecOf returns the TypedCodec of the binary serializers of this package.
+func typedCodecOf(s interface{}, typ reflect.Type) (binaryx.TypedCodec, bool) {
+	var (
+		tc *binaryx.TypedCodec
+		ok bool
+	)
+
+	switch s := s.(type) {
+	case *BinarySerializer:
+		tc, ok = s.typedCodec(typ)
+	case *RawBinarySerializer:
+		tc, ok = s.typedCodec(typ)
+	}
+
+	if !ok {
+		return binaryx.TypedCodec{}, false
+	}
+
+	return *tc, true
+}
+
+// typeOf returns the reflect.Type of T, interface types included.
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// Encode returns the payload of v serialized by s. The binary serializers resolve the plan of T once and reuse it for
+// every later value, rather than looking the dynamic type of v up on each call; other serializers get v as is.
+func Encode[T any](s models.Serializer, v T) ([]byte, error) {
+	if tc, ok := binaryx.TypedCodecOf(s, typeOf[T]()); ok {
+		return typedCodec[T]{tc: tc}.Encode(v)
+	}
+
+	return serializerCodec[T]{s: s}.Encode(v)
+}
+
+// Decode returns the value of T deserialized from payload by s, the plan of T being resolved once like Encode does.
+// When T is a pointer type, the value is decoded into a newly allocated one.
+func Decode[T any](s models.Serializer, payload []byte) (T, error) {
+	if tc, ok := binaryx.TypedCodecOf(s, typeOf[T]()); ok {
+		return typedCodec[T]{tc: tc}.Decode(payload)
+	}
+
+	return serializerCodec[T]{s: s}.Decode(payload)
+}
+
+// NewCodec returns the models.Codec serializing the values of T with s. With the binary serializers, it holds the plan
+// of T, sparing even the lookups Encode and Decode make.
+func NewCodec[T any](s models.Serializer) models.Codec[T] {
+	if tc, ok := binaryx.TypedCodecOf(s, typeOf[T]()); ok {
+		return typedCodec[T]{tc: tc}
+	}
+
+	return serializerCodec[T]{s: s}
+}
+
+// typedCodec is the models.Codec of the binary serializers.
+type typedCodec[T any] struct {
+	tc binaryx.TypedCodec
+}
+
+func (c typedCodec[T]) Encode(v T) ([]byte, error) {
+	return c.tc.Encode(unsafe.Pointer(&v))
+}
+
+func (c typedCodec[T]) Decode(payload []byte) (T, error) {
+	var v T
+	if err := c.tc.Decode(payload, unsafe.Pointer(&v)); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return v, nil
+}
+
+// serializerCodec is the models.Codec of the other serializers, going through models.Serializer.
+type serializerCodec[T any] struct {
+	s models.Serializer
+}
+
+func (c serializerCodec[T]) Encode(v T) ([]byte, error) {
+	return c.s.Serialize(v)
+}
+
+func (c serializerCodec[T]) Decode(payload []byte) (T, error) {
+	var v T
+	target := interface{}(&v)
+	if typ := typeOf[T](); typ.Kind() == reflect.Ptr {
+		// the binary serializers, which decorators may wrap, only fill the value a pointer points to
+		v = reflect.New(typ.Elem()).Interface().(T)
+		target = v
+	}
+
+	if err := c.s.Deserialize(payload, target); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return v, nil
+}
@@ -0,0 +1,125 @@
+//go:build unit
+
+package serializer_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/pietroski-software-company/devex/golang/serializer"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/internal/testmodels"
+	"gitlab.com/pietroski-software-company/devex/golang/serializer/models"
+)
+
+// lateCount gets its codec once a models.Codec was built for it, see TestTypedAPI.
+type lateCount struct {
+	N uint64
+}
+
+// checkTyped checks that Encode and NewCodec write the payload Serialize does for v, and that Decode and NewCodec
+// read v back from it.
+func checkTyped[T any](t *testing.T, s models.Serializer, v T) {
+	t.Helper()
+
+	want, err := s.Serialize(v)
+	require.NoError(t, err)
+
+	bs, err := serializer.Encode(s, v)
+	require.NoError(t, err)
+	assert.Equal(t, want, bs)
+
+	decoded, err := serializer.Decode[T](s, bs)
+	require.NoError(t, err)
+	assert.Equal(t, v, decoded)
+
+	c := serializer.NewCodec[T](s)
+	bs, err = c.Encode(v)
+	require.NoError(t, err)
+	assert.Equal(t, want, bs)
+
+	decoded, err = c.Decode(bs)
+	require.NoError(t, err)
+	assert.Equal(t, v, decoded)
+}
+
+func TestTypedAPI(t *testing.T) {
+	item := testmodels.Item{
+		Id:      "item",
+		ItemId:  7,
+		Number:  -7,
+		SubItem: &testmodels.SubItem{Date: 1, Amount: 2, ItemCode: "code"},
+	}
+
+	t.Run("same payload as Serialize", func(t *testing.T) {
+		for _, opts := range [][]serializer.BinaryOption{
+			nil,
+			{serializer.WithVarint()},
+			{serializer.WithEnvelope(), serializer.WithSchemaFingerprint(), serializer.WithChecksum()},
+		} {
+			for name, s := range allGeneratedSerializers(opts...) {
+				t.Run(name, func(t *testing.T) {
+					checkTyped(t, s, item)
+					checkTyped(t, s, &item)
+					checkTyped(t, s, &testmodels.TreeNode{Value: 1, Children: []*testmodels.TreeNode{{Value: 2}, nil}})
+					checkTyped(t, s, map[string]testmodels.Item{"item": item})
+					checkTyped(t, s, []string{"a", "", "c"})
+					checkTyped(t, s, []byte{1, 2, 3})
+					checkTyped(t, s, [3]float64{1.5, -2, 0})
+					checkTyped(t, s, "text")
+					checkTyped(t, s, int64(-300))
+					checkTyped(t, s, time.Date(2024, 3, 9, 12, 30, 0, 0, time.UTC))
+				})
+			}
+		}
+	})
+
+	t.Run("other serializers", func(t *testing.T) {
+		s := serializer.NewJsonSerializer()
+		checkTyped(t, s, item)
+		checkTyped(t, s, &item)
+		checkTyped(t, s, []string{"a", "b"})
+
+		_, err := serializer.Decode[testmodels.Item](s, []byte("{"))
+		assert.Error(t, err)
+	})
+
+	t.Run("decorated binary serializers", func(t *testing.T) {
+		s := serializer.WithCompression(
+			serializer.NewBinarySerializer(), serializer.Gzip, serializer.WithCompressionThreshold(0),
+		)
+		checkTyped(t, s, item)
+		checkTyped(t, s, &item)
+	})
+
+	t.Run("plan resolved again once a codec is registered", func(t *testing.T) {
+		s := serializer.NewBinarySerializer()
+		c := serializer.NewCodec[lateCount](s)
+
+		bs, err := c.Encode(lateCount{N: 300})
+		require.NoError(t, err)
+		assert.Len(t, bs, 8)
+
+		serializer.RegisterCodec(
+			func(w *serializer.Writer, v lateCount) error {
+				w.WriteUint16(uint16(v.N))
+				return nil
+			},
+			func(r *serializer.Reader, v *lateCount) error {
+				n, err := r.ReadUint16()
+				v.N = uint64(n)
+				return err
+			},
+		)
+
+		bs, err = c.Encode(lateCount{N: 300})
+		require.NoError(t, err)
+		assert.Len(t, bs, 2)
+
+		decoded, err := c.Decode(bs)
+		require.NoError(t, err)
+		assert.Equal(t, lateCount{N: 300}, decoded)
+	})
+}